@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// healthHandler serves the liveness and readiness endpoints of the
+// controller, and /sentryz reporting whether the Sentry API is reachable. The
+// controller is live as long as it serves requests, and ready once it can
+// serve the webhooks and reconcile from a synced cache.
+//
+// Readiness deliberately ignores Sentry: the replicas also serve the admission
+// and conversion webhooks, which the API server requires for every write of
+// the sentry objects, so a Sentry outage must not take them out of their
+// service. /sentryz is meant for monitoring, not as a probe.
+func healthHandler(ready, sentry func(context.Context) error) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.Handle("/readyz", checkHandler(ready))
+	mux.Handle("/sentryz", checkHandler(sentry))
+	return mux
+}
+
+func checkHandler(check func(context.Context) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
+		defer cancel()
+		if err := check(ctx); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+}
+
+// localReady returns a readiness check that fails until synced is set, i.e.
+// the cache of the manager is synced, and while the webhook server does not
+// accept connections on webhookAddr, if not empty.
+func localReady(synced *int32, webhookAddr string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		if atomic.LoadInt32(synced) == 0 {
+			return fmt.Errorf("cache not synced")
+		}
+		if webhookAddr == "" {
+			return nil
+		}
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", webhookAddr)
+		if err != nil {
+			return fmt.Errorf("webhook server not serving: %s", err)
+		}
+		return conn.Close()
+	}
+}
+
+// sentryReachable checks that the root of the Sentry API can be requested
+// with the given client, i.e. that the API is reachable and accepts the token
+// of the client. Requests wait for limiter like those of the Sentry clients,
+// and their outcome is reused for interval. The check always succeeds if hc is
+// nil, i.e. when there is no default token and organizations bring their own
+// credentials.
+type sentryReachable struct {
+	hc       *http.Client
+	endpoint *url.URL
+	limiter  *rate.Limiter
+	interval time.Duration
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+func (c *sentryReachable) check(ctx context.Context) error {
+	if c.hc == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checked.IsZero() && time.Since(c.checked) < c.interval {
+		return c.err
+	}
+
+	err := c.request(ctx)
+	if ctx.Err() != nil {
+		// The probe gave up, which says nothing about Sentry.
+		return err
+	}
+	c.checked, c.err = time.Now(), err
+	return err
+}
+
+func (c *sentryReachable) request(ctx context.Context) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodGet, c.endpoint.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("sentry api unreachable: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sentry api returned %s", resp.Status)
+	}
+	return nil
+}
@@ -1,19 +1,32 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sr/kube-sentry-controller/pkg/apis"
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
 	"github.com/sr/kube-sentry-controller/pkg/controller"
 	"github.com/sr/kube-sentry-controller/pkg/sentry"
+	"github.com/sr/kube-sentry-controller/pkg/webhook"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
 )
@@ -27,25 +40,79 @@ func main() {
 
 func run() error {
 	opts := &struct {
-		apiEndpoint string
-		apiToken    string
-		timeout     time.Duration
+		apiEndpoint   string
+		apiToken      string
+		apiRateLimit  float64
+		apiRateBurst  int
+		apiMaxRetries int
+		timeout       time.Duration
+		deletion      string
+		resync        time.Duration
+		metricsAddr   string
+		healthAddr    string
+		shutdown      time.Duration
+		namespaces    string
+		selector      string
+
+		webhookPort       int
+		webhookCertDir    string
+		webhookSelfSigned string
+		defaultOrg        string
+
+		leaderElect             bool
+		leaderElectionNamespace string
+		leaderElectionID        string
+		leaderElectionLease     time.Duration
+		leaderElectionRenew     time.Duration
+		leaderElectionRetry     time.Duration
 	}{
-		apiEndpoint: "https://sentry.io/api/0/",
-		timeout:     10 * time.Second,
+		apiEndpoint:   "https://sentry.io/api/0/",
+		apiRateLimit:  10,
+		apiRateBurst:  20,
+		apiMaxRetries: sentry.DefaultRetryPolicy.MaxRetries,
+		timeout:       10 * time.Second,
+		deletion:      string(sentryv1alpha1.DeletionPolicyDelete),
+		resync:        10 * time.Minute,
+		metricsAddr:   ":8080",
+		healthAddr:    ":8081",
+		shutdown:      30 * time.Second,
+
+		webhookCertDir: filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs"),
+
+		leaderElectionID:    "kube-sentry-controller",
+		leaderElectionLease: 15 * time.Second,
+		leaderElectionRenew: 10 * time.Second,
+		leaderElectionRetry: 2 * time.Second,
 	}
 
 	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 	fs.StringVar(&opts.apiEndpoint, "api-endpoint", opts.apiEndpoint, "Sentry API endpoint")
-	fs.StringVar(&opts.apiToken, "api-token", "", "Sentry API auth token")
+	fs.StringVar(&opts.apiToken, "api-token", "", "Sentry API auth token for organizations without an Organization object")
+	fs.Float64Var(&opts.apiRateLimit, "api-rate-limit", opts.apiRateLimit, "Maximum number of Sentry API requests per second, shared by all controllers")
+	fs.IntVar(&opts.apiRateBurst, "api-rate-burst", opts.apiRateBurst, "Maximum burst of Sentry API requests above the rate limit")
+	fs.IntVar(&opts.apiMaxRetries, "api-max-retries", opts.apiMaxRetries, "Maximum number of times a Sentry API request failing with a transient error is retried")
 	fs.DurationVar(&opts.timeout, "timeout", opts.timeout, "Timeout for a single reconcilation attempt")
+	fs.StringVar(&opts.metricsAddr, "metrics-addr", opts.metricsAddr, "Address the Prometheus metrics endpoint binds to, 0 to disable")
+	fs.StringVar(&opts.namespaces, "namespace", "", "Comma-separated list of namespaces to watch, defaults to all namespaces")
+	fs.StringVar(&opts.selector, "selector", "", "Label selector restricting the Teams, Projects and ClientKeys reconciled, e.g. tenant=foo. Objects not matching it are still listed, watched and cached, use -namespace to cut memory and API server load")
+	fs.IntVar(&opts.webhookPort, "webhook-port", 0, "Port the admission webhooks are served on, 0 to disable")
+	fs.StringVar(&opts.webhookCertDir, "webhook-cert-dir", opts.webhookCertDir, "Directory holding the tls.crt and tls.key files of the webhook server")
+	fs.StringVar(&opts.webhookSelfSigned, "webhook-self-signed", "", "Comma-separated DNS names of the webhook service to write a self-signed certificate for to webhook-cert-dir, for local clusters")
+	fs.StringVar(&opts.defaultOrg, "default-organization", "", "Organization the webhook sets on objects without one, in namespaces without the sentry.sr.github.com/organization annotation")
+	fs.StringVar(&opts.healthAddr, "health-addr", opts.healthAddr, "Address the /healthz, /readyz and /sentryz endpoints bind to, 0 to disable")
+	fs.DurationVar(&opts.shutdown, "shutdown-timeout", opts.shutdown, "Maximum time to wait for in-flight reconciles to complete when exiting")
+	fs.BoolVar(&opts.leaderElect, "leader-elect", false, "Elect a leader among the replicas of the controller, so that only one reconciles objects at a time")
+	fs.StringVar(&opts.leaderElectionNamespace, "leader-election-namespace", "", "Namespace of the leader election lock, defaults to the namespace of the controller")
+	fs.StringVar(&opts.leaderElectionID, "leader-election-id", opts.leaderElectionID, "Name of the leader election lock")
+	fs.DurationVar(&opts.leaderElectionLease, "leader-election-lease-duration", opts.leaderElectionLease, "Duration non-leader replicas wait before trying to acquire leadership")
+	fs.DurationVar(&opts.leaderElectionRenew, "leader-election-renew-deadline", opts.leaderElectionRenew, "Duration the leader retries renewing leadership before giving it up")
+	fs.DurationVar(&opts.leaderElectionRetry, "leader-election-retry-period", opts.leaderElectionRetry, "Duration replicas wait between leader election attempts")
+	fs.DurationVar(&opts.resync, "resync-period", opts.resync, "Period after which objects are synced again to revert changes made in Sentry, 0 to disable")
+	fs.StringVar(&opts.deletion, "deletion-policy", opts.deletion, "Deletion policy of objects that do not set one, Delete or Retain")
 	if err := fs.Parse(os.Args[1:]); err != nil {
 		return err
 	}
 
-	if opts.apiToken == "" {
-		return fmt.Errorf("required flag missing: api-token")
-	}
 	if opts.apiEndpoint == "" {
 		return fmt.Errorf("required flag missing: api-endpoint")
 	}
@@ -53,6 +120,21 @@ func run() error {
 	if err != nil {
 		return err
 	}
+	deletionPolicy := sentryv1alpha1.DeletionPolicy(opts.deletion)
+	if deletionPolicy != sentryv1alpha1.DeletionPolicyDelete && deletionPolicy != sentryv1alpha1.DeletionPolicyRetain {
+		return fmt.Errorf("invalid deletion-policy %q, must be Delete or Retain", opts.deletion)
+	}
+
+	var namespaces []string
+	if opts.namespaces != "" {
+		namespaces = strings.Split(opts.namespaces, ",")
+	}
+	var selector labels.Selector
+	if opts.selector != "" {
+		if selector, err = labels.Parse(opts.selector); err != nil {
+			return errors.Wrapf(err, "invalid selector %q", opts.selector)
+		}
+	}
 
 	logf.SetLogger(logf.ZapLogger(true))
 	logger := logf.Log.WithName("kube-sentry-controller")
@@ -62,7 +144,19 @@ func run() error {
 		return errors.Wrap(err, "failed to set up kubernetes client config")
 	}
 
-	mgr, err := manager.New(cfg, manager.Options{})
+	mgr, err := manager.New(cfg, manager.Options{
+		MetricsBindAddress: opts.metricsAddr,
+		NewCache:           sentrycontroller.NewCache(namespaces),
+		Port:               opts.webhookPort,
+		CertDir:            opts.webhookCertDir,
+
+		LeaderElection:          opts.leaderElect,
+		LeaderElectionNamespace: opts.leaderElectionNamespace,
+		LeaderElectionID:        opts.leaderElectionID,
+		LeaseDuration:           &opts.leaderElectionLease,
+		RenewDeadline:           &opts.leaderElectionRenew,
+		RetryPeriod:             &opts.leaderElectionRetry,
+	})
 	if err != nil {
 		return errors.Wrap(err, "failed to set up controller manager")
 	}
@@ -71,28 +165,150 @@ func run() error {
 		return errors.Wrap(err, "failed to add APIs to scheme")
 	}
 
-	cli := sentry.New(
-		&http.Client{
-			Transport: &tokenTransport{
-				transport: http.DefaultTransport,
-				token:     opts.apiToken,
-			},
-		},
-		ep,
+	// All clients share the same rate limiter and metrics.
+	limiter := rate.NewLimiter(rate.Limit(opts.apiRateLimit), opts.apiRateBurst)
+	sentryMetrics := sentry.NewMetrics()
+	if err := metrics.Registry.Register(sentryMetrics); err != nil {
+		return errors.Wrap(err, "failed to register sentry api metrics")
+	}
+	newSentry := func(token, endpoint string) (sentry.Client, error) {
+		u := ep
+		if endpoint != "" {
+			var err error
+			if u, err = url.Parse(endpoint); err != nil {
+				return nil, errors.Wrapf(err, "invalid sentry api endpoint %q", endpoint)
+			}
+		}
+		return sentry.New(
+			tokenClient(token),
+			u,
+			sentry.WithRateLimiter(limiter),
+			sentry.WithMetrics(sentryMetrics),
+			sentry.WithRetryPolicy(sentry.RetryPolicy{
+				MaxRetries: opts.apiMaxRetries,
+				MinBackoff: sentry.DefaultRetryPolicy.MinBackoff,
+				MaxBackoff: sentry.DefaultRetryPolicy.MaxBackoff,
+			}),
+		), nil
+	}
+
+	var (
+		cli sentry.Client
+		hc  *http.Client
 	)
+	if opts.apiToken != "" {
+		if cli, err = newSentry(opts.apiToken, ""); err != nil {
+			return err
+		}
+		hc = tokenClient(opts.apiToken)
+	}
 
-	if err := sentrycontroller.Add(mgr, logger, cli, opts.timeout); err != nil {
+	inflight := &sentrycontroller.InFlight{}
+
+	err = sentrycontroller.Add(mgr, logger, sentrycontroller.Options{
+		Sentry:    cli,
+		NewSentry: newSentry,
+		Timeout:   opts.timeout,
+
+		DeletionPolicy: deletionPolicy,
+		ResyncPeriod:   opts.resync,
+		Selector:       selector,
+		InFlight:       inflight,
+	})
+	if err != nil {
 		return errors.Wrap(err, "failed to registry sentry controllers with the manager")
 	}
 
+	if opts.webhookPort != 0 {
+		if opts.webhookSelfSigned != "" {
+			if err := bootstrapWebhookCert(cfg, mgr, opts.webhookCertDir, strings.Split(opts.webhookSelfSigned, ",")); err != nil {
+				return err
+			}
+		}
+		err := sentrywebhook.Add(mgr, sentrywebhook.Options{
+			DefaultOrganization: opts.defaultOrg,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to register webhooks with the manager")
+		}
+	}
+
+	stop := signals.SetupSignalHandler()
+
+	var health *http.Server
+	if opts.healthAddr != "0" {
+		var synced int32
+		go func() {
+			if mgr.GetCache().WaitForCacheSync(stop) {
+				atomic.StoreInt32(&synced, 1)
+			}
+		}()
+		var webhookAddr string
+		if opts.webhookPort != 0 {
+			webhookAddr = net.JoinHostPort("localhost", strconv.Itoa(opts.webhookPort))
+		}
+		reachable := &sentryReachable{hc: hc, endpoint: ep, limiter: limiter, interval: time.Minute}
+		health = &http.Server{
+			Addr:    opts.healthAddr,
+			Handler: healthHandler(localReady(&synced, webhookAddr), reachable.check),
+		}
+		go func() {
+			if err := health.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error(err, "health endpoints failed")
+			}
+		}()
+	}
+
 	logger.Info("starting...")
-	if err := mgr.Start(signals.SetupSignalHandler()); err != nil {
+	err = mgr.Start(stop)
+
+	// Let in-flight reconciles complete their Sentry API calls and record
+	// the outcome, rather than leave objects half synced.
+	logger.Info("waiting for in-flight reconciles...")
+	ctx, cancel := context.WithTimeout(context.Background(), opts.shutdown)
+	defer cancel()
+	if werr := inflight.Wait(ctx); werr != nil {
+		logger.Info("timed out waiting for in-flight reconciles")
+	}
+	if health != nil {
+		_ = health.Shutdown(ctx)
+	}
+
+	if err != nil {
 		return errors.Wrap(err, "failed to run the manager")
 	}
 	logger.Info("exiting...")
 	return nil
 }
 
+// bootstrapWebhookCert writes a self-signed certificate for the webhook server
+// and configures the API server to trust it. The manager client cannot be used
+// until the manager is started.
+func bootstrapWebhookCert(cfg *rest.Config, mgr manager.Manager, dir string, dnsNames []string) error {
+	ca, err := sentrywebhook.WriteSelfSignedCert(dir, dnsNames)
+	if err != nil {
+		return errors.Wrap(err, "failed to write self-signed webhook certificate")
+	}
+	kube, err := client.New(cfg, client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return errors.Wrap(err, "failed to set up kubernetes client")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return sentrywebhook.InjectCABundle(ctx, kube, ca)
+}
+
+// tokenClient returns an HTTP client authenticating requests with the given
+// Sentry API token.
+func tokenClient(token string) *http.Client {
+	return &http.Client{
+		Transport: &tokenTransport{
+			transport: http.DefaultTransport,
+			token:     token,
+		},
+	}
+}
+
 type tokenTransport struct {
 	transport http.RoundTripper
 	token     string
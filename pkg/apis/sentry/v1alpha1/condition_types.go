@@ -0,0 +1,73 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionType is the type of a Condition.
+type ConditionType string
+
+const (
+	// ConditionReady indicates that the Sentry object exists and matches the spec.
+	ConditionReady ConditionType = "Ready"
+	// ConditionSynced indicates whether the last attempt to reconcile the
+	// object against the Sentry API succeeded.
+	ConditionSynced ConditionType = "Synced"
+	// ConditionDrifted indicates whether the last sync found the Sentry object
+	// changed outside of the controller, and reverted the change.
+	ConditionDrifted ConditionType = "Drifted"
+)
+
+// Condition describes the state of a Sentry object at a certain point.
+type Condition struct {
+	// Type of the condition.
+	Type ConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// LastTransitionTime is the last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a brief CamelCase reason for the condition's last transition.
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable message indicating details about the transition.
+	Message string `json:"message,omitempty"`
+}
+
+// ConditionedStatus contains the status fields shared by all Sentry objects.
+type ConditionedStatus struct {
+	// ObservedGeneration is the most recent generation successfully synced with Sentry.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastSyncTime is the last time the object was successfully synced with Sentry.
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// Conditions are the latest available observations of the object's state.
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// GetCondition returns the condition of the given type, or nil if it is not set.
+func (s *ConditionedStatus) GetCondition(t ConditionType) *Condition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == t {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds or replaces the condition of the same type, preserving its
+// LastTransitionTime if the status did not change.
+func (s *ConditionedStatus) SetCondition(c Condition) {
+	if existing := s.GetCondition(c.Type); existing != nil {
+		if existing.Status == c.Status {
+			c.LastTransitionTime = existing.LastTransitionTime
+		}
+		*existing = c
+		return
+	}
+	s.Conditions = append(s.Conditions, c)
+}
+
+// IsReady returns true if the Ready condition is True.
+func (s *ConditionedStatus) IsReady() bool {
+	c := s.GetCondition(ConditionReady)
+	return c != nil && c.Status == corev1.ConditionTrue
+}
@@ -0,0 +1,14 @@
+package v1alpha1
+
+// v1alpha1 is the hub version of the Team, Project and ClientKey types: the
+// other versions convert to and from it, and it is the version objects are
+// stored in.
+
+// Hub marks Team as a conversion hub.
+func (*Team) Hub() {}
+
+// Hub marks Project as a conversion hub.
+func (*Project) Hub() {}
+
+// Hub marks ClientKey as a conversion hub.
+func (*ClientKey) Hub() {}
@@ -0,0 +1,64 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OrganizationSpec defines the desired state of Organization
+type OrganizationSpec struct {
+	// Slug of the Sentry organization. Teams, projects and client keys whose
+	// organization matches this slug are managed with the credentials of this
+	// Organization.
+	Slug string `json:"slug"`
+	// SecretRef references the Secret holding the API credentials.
+	SecretRef OrganizationSecretReference `json:"secretRef"`
+}
+
+// OrganizationSecretReference references the Secret holding the Sentry API
+// auth token and, optionally, the API endpoint of an organization.
+type OrganizationSecretReference struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// TokenKey is the key of the API auth token in the Secret. Defaults to "token".
+	TokenKey string `json:"tokenKey,omitempty"`
+	// EndpointKey is the key of the API endpoint in the Secret. Defaults to
+	// "endpoint". The controller's default endpoint is used if the key is not set.
+	EndpointKey string `json:"endpointKey,omitempty"`
+}
+
+// OrganizationStatus defines the observed state of Organization
+type OrganizationStatus struct {
+	Slug string `json:"slug,omitempty"`
+	Name string `json:"name,omitempty"`
+
+	ConditionedStatus `json:",inline"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Organization is the Schema for the organizations API
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+type Organization struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OrganizationSpec   `json:"spec,omitempty"`
+	Status OrganizationStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OrganizationList contains a list of Organization
+type OrganizationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Organization `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Organization{}, &OrganizationList{})
+}
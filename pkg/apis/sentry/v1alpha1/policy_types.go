@@ -0,0 +1,14 @@
+package v1alpha1
+
+// DeletionPolicy describes what happens to the Sentry object when the
+// Kubernetes object managing it is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the Sentry object.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain leaves the Sentry object, and the data attached to
+	// it, untouched in Sentry.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+)
@@ -1,14 +1,62 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ProjectSpec defines the desired state of Project
 type ProjectSpec struct {
-	OrganizationSlug string `json:"organization"`
-	TeamSlug         string `json:"team"`
-	Slug             string `json:"slug"`
+	// OrganizationSlug defaults to the organization of the team referenced
+	// by TeamRef.
+	OrganizationSlug string `json:"organization,omitempty"`
+	// TeamSlug is the slug of the team owning the project. Ignored if TeamRef
+	// is set.
+	TeamSlug string `json:"team,omitempty"`
+	// TeamRef references the Team, in the same namespace, owning the project.
+	// The project is not created until the Team is Ready.
+	TeamRef *corev1.LocalObjectReference `json:"teamRef,omitempty"`
+	// Teams are the slugs of additional teams the project belongs to.
+	Teams []string `json:"teams,omitempty"`
+	// TeamRefs reference additional Teams, in the same namespace, the project
+	// belongs to.
+	TeamRefs []corev1.LocalObjectReference `json:"teamRefs,omitempty"`
+	Slug     string                        `json:"slug"`
+	// Name is the display name of the project. Defaults to the slug.
+	Name string `json:"name,omitempty"`
+	// Adopt an existing Sentry project with the same slug instead of failing
+	// to create it. Its settings and teams are reconciled with the spec from
+	// then on.
+	Adopt bool `json:"adopt,omitempty"`
+
+	// The following settings are left unmanaged when not set. Settings that
+	// are set are reconciled, reverting changes made in the Sentry UI.
+
+	// Platform of the project, e.g. "go" or "javascript-react".
+	Platform string `json:"platform,omitempty"`
+	// DefaultEnvironment selected in the Sentry UI.
+	DefaultEnvironment string `json:"defaultEnvironment,omitempty"`
+	// ResolveAge is the number of hours after which issues are automatically
+	// resolved. 0 disables automatic resolution.
+	// +kubebuilder:validation:Minimum=0
+	ResolveAge *int32 `json:"resolveAge,omitempty"`
+	// SubjectPrefix of email notifications.
+	SubjectPrefix string `json:"subjectPrefix,omitempty"`
+	// DataScrubber enables server-side scrubbing of sensitive data.
+	DataScrubber *bool `json:"dataScrubber,omitempty"`
+	// SensitiveFields are additional field names to scrub.
+	SensitiveFields []string `json:"sensitiveFields,omitempty"`
+	// SafeFields are field names never to scrub.
+	SafeFields []string `json:"safeFields,omitempty"`
+	// ScrubIPAddresses prevents IP addresses from being stored.
+	ScrubIPAddresses *bool `json:"scrubIPAddresses,omitempty"`
+	// AllowedDomains are the origins allowed to submit events.
+	AllowedDomains []string `json:"allowedDomains,omitempty"`
+
+	// DeletionPolicy of the Sentry project. Deleting a project also deletes
+	// its issues and events; use Retain to keep them. Defaults to the
+	// controller-wide policy.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 // ProjectStatus defines the observed state of Project
@@ -16,6 +64,11 @@ type ProjectStatus struct {
 	OrganizationSlug string `json:"organization"`
 	TeamSlug         string `json:"team"`
 	Slug             string `json:"slug"`
+	Name             string `json:"name,omitempty"`
+	// Teams are the slugs of all the teams the project belongs to.
+	Teams []string `json:"teams,omitempty"`
+
+	ConditionedStatus `json:",inline"`
 }
 
 // +genclient
@@ -23,6 +76,14 @@ type ProjectStatus struct {
 
 // Project is the Schema for the sentryprojects API
 // +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=projects,shortName=sproj,categories=sentry
+// +kubebuilder:printcolumn:name="Organization",type="string",JSONPath=".status.organization"
+// +kubebuilder:printcolumn:name="Slug",type="string",JSONPath=".status.slug"
+// +kubebuilder:printcolumn:name="Team",type="string",JSONPath=".status.team"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:storageversion
 type Project struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -8,12 +8,24 @@ import (
 type TeamSpec struct {
 	Slug             string `json:"slug"`
 	OrganizationSlug string `json:"organization"`
+	// Name is the display name of the team. Defaults to the slug.
+	Name string `json:"name,omitempty"`
+	// Adopt an existing Sentry team with the same slug instead of failing to
+	// create it. The team is managed by the controller from then on.
+	Adopt bool `json:"adopt,omitempty"`
+
+	// DeletionPolicy of the Sentry team. Defaults to the policy the controller
+	// is configured with, Delete unless set otherwise.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 // TeamStatus defines the observed state of Team
 type TeamStatus struct {
 	Slug             string `json:"slug"`
+	Name             string `json:"name,omitempty"`
 	OrganizationSlug string `json:"organization"`
+
+	ConditionedStatus `json:",inline"`
 }
 
 // +genclient
@@ -21,6 +33,13 @@ type TeamStatus struct {
 
 // Team is the Schema for the sentryteams API
 // +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=teams,shortName=stm,categories=sentry
+// +kubebuilder:printcolumn:name="Organization",type="string",JSONPath=".status.organization"
+// +kubebuilder:printcolumn:name="Slug",type="string",JSONPath=".status.slug"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:storageversion
 type Team struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -5,6 +5,8 @@
 package v1alpha1
 
 import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -13,8 +15,8 @@ func (in *ClientKey) DeepCopyInto(out *ClientKey) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientKey.
@@ -35,11 +37,70 @@ func (in *ClientKey) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientKeyBrowserSDK) DeepCopyInto(out *ClientKeyBrowserSDK) {
+	*out = *in
+	if in.Replay != nil {
+		in, out := &in.Replay, &out.Replay
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Performance != nil {
+		in, out := &in.Performance, &out.Performance
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Debug != nil {
+		in, out := &in.Debug, &out.Debug
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientKeyBrowserSDK.
+func (in *ClientKeyBrowserSDK) DeepCopy() *ClientKeyBrowserSDK {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientKeyBrowserSDK)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientKeyConfigMap) DeepCopyInto(out *ClientKeyConfigMap) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientKeyConfigMap.
+func (in *ClientKeyConfigMap) DeepCopy() *ClientKeyConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientKeyConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientKeyList) DeepCopyInto(out *ClientKeyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	out.ListMeta = in.ListMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
 		*out = make([]ClientKey, len(*in))
@@ -67,9 +128,137 @@ func (in *ClientKeyList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientKeyPreviousKey) DeepCopyInto(out *ClientKeyPreviousKey) {
+	*out = *in
+	in.RotatedAt.DeepCopyInto(&out.RotatedAt)
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientKeyPreviousKey.
+func (in *ClientKeyPreviousKey) DeepCopy() *ClientKeyPreviousKey {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientKeyPreviousKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientKeyRateLimit) DeepCopyInto(out *ClientKeyRateLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientKeyRateLimit.
+func (in *ClientKeyRateLimit) DeepCopy() *ClientKeyRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientKeyRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientKeyRotationPolicy) DeepCopyInto(out *ClientKeyRotationPolicy) {
+	*out = *in
+	if in.Period != nil {
+		in, out := &in.Period, &out.Period
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientKeyRotationPolicy.
+func (in *ClientKeyRotationPolicy) DeepCopy() *ClientKeyRotationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientKeyRotationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientKeySecretTemplate) DeepCopyInto(out *ClientKeySecretTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientKeySecretTemplate.
+func (in *ClientKeySecretTemplate) DeepCopy() *ClientKeySecretTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientKeySecretTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientKeySpec) DeepCopyInto(out *ClientKeySpec) {
 	*out = *in
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.IsActive != nil {
+		in, out := &in.IsActive, &out.IsActive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(ClientKeyRateLimit)
+		**out = **in
+	}
+	if in.BrowserSDK != nil {
+		in, out := &in.BrowserSDK, &out.BrowserSDK
+		*out = new(ClientKeyBrowserSDK)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretTemplate != nil {
+		in, out := &in.SecretTemplate, &out.SecretTemplate
+		*out = new(ClientKeySecretTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ClientKeyConfigMap)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RotationPolicy != nil {
+		in, out := &in.RotationPolicy, &out.RotationPolicy
+		*out = new(ClientKeyRotationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientKeySpec.
@@ -85,6 +274,28 @@ func (in *ClientKeySpec) DeepCopy() *ClientKeySpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientKeyStatus) DeepCopyInto(out *ClientKeyStatus) {
 	*out = *in
+	if in.KeyCreationTime != nil {
+		in, out := &in.KeyCreationTime, &out.KeyCreationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PreviousKeys != nil {
+		in, out := &in.PreviousKeys, &out.PreviousKeys
+		*out = make([]ClientKeyPreviousKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(ClientKeyRateLimit)
+		**out = **in
+	}
+	if in.BrowserSDK != nil {
+		in, out := &in.BrowserSDK, &out.BrowserSDK
+		*out = new(ClientKeyBrowserSDK)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientKeyStatus.
@@ -98,12 +309,160 @@ func (in *ClientKeyStatus) DeepCopy() *ClientKeyStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Project) DeepCopyInto(out *Project) {
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConditionedStatus) DeepCopyInto(out *ConditionedStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionedStatus.
+func (in *ConditionedStatus) DeepCopy() *ConditionedStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConditionedStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Organization) DeepCopyInto(out *Organization) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Organization.
+func (in *Organization) DeepCopy() *Organization {
+	if in == nil {
+		return nil
+	}
+	out := new(Organization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Organization) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrganizationList) DeepCopyInto(out *OrganizationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Organization, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationList.
+func (in *OrganizationList) DeepCopy() *OrganizationList {
+	if in == nil {
+		return nil
+	}
+	out := new(OrganizationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OrganizationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrganizationSecretReference) DeepCopyInto(out *OrganizationSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationSecretReference.
+func (in *OrganizationSecretReference) DeepCopy() *OrganizationSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(OrganizationSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrganizationSpec) DeepCopyInto(out *OrganizationSpec) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationSpec.
+func (in *OrganizationSpec) DeepCopy() *OrganizationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OrganizationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrganizationStatus) DeepCopyInto(out *OrganizationStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationStatus.
+func (in *OrganizationStatus) DeepCopy() *OrganizationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OrganizationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Project) DeepCopyInto(out *Project) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Project.
@@ -128,7 +487,7 @@ func (in *Project) DeepCopyObject() runtime.Object {
 func (in *ProjectList) DeepCopyInto(out *ProjectList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	out.ListMeta = in.ListMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
 		*out = make([]Project, len(*in))
@@ -159,6 +518,51 @@ func (in *ProjectList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
 	*out = *in
+	if in.TeamRef != nil {
+		in, out := &in.TeamRef, &out.TeamRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TeamRefs != nil {
+		in, out := &in.TeamRefs, &out.TeamRefs
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResolveAge != nil {
+		in, out := &in.ResolveAge, &out.ResolveAge
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DataScrubber != nil {
+		in, out := &in.DataScrubber, &out.DataScrubber
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SensitiveFields != nil {
+		in, out := &in.SensitiveFields, &out.SensitiveFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SafeFields != nil {
+		in, out := &in.SafeFields, &out.SafeFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScrubIPAddresses != nil {
+		in, out := &in.ScrubIPAddresses, &out.ScrubIPAddresses
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowedDomains != nil {
+		in, out := &in.AllowedDomains, &out.AllowedDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSpec.
@@ -174,6 +578,12 @@ func (in *ProjectSpec) DeepCopy() *ProjectSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectStatus) DeepCopyInto(out *ProjectStatus) {
 	*out = *in
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectStatus.
@@ -192,7 +602,7 @@ func (in *Team) DeepCopyInto(out *Team) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Team.
@@ -217,7 +627,7 @@ func (in *Team) DeepCopyObject() runtime.Object {
 func (in *TeamList) DeepCopyInto(out *TeamList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	out.ListMeta = in.ListMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
 		*out = make([]Team, len(*in))
@@ -263,6 +673,7 @@ func (in *TeamSpec) DeepCopy() *TeamSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TeamStatus) DeepCopyInto(out *TeamStatus) {
 	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamStatus.
@@ -0,0 +1,196 @@
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClientKeySpec defines the desired state of ClientKey
+type ClientKeySpec struct {
+	// OrganizationSlug is the slug of the organization of the key. Defaults
+	// to the organization of the referenced Project.
+	OrganizationSlug string `json:"organizationSlug,omitempty"`
+	// Project of the key.
+	Project ProjectReference `json:"project"`
+	// DisplayName of the key.
+	DisplayName string `json:"displayName"`
+	// Adopt an existing Sentry key instead of creating a new one: the key
+	// with ID KeyID, or the only key of the project named DisplayName if
+	// KeyID is not set. A new key is created if no key has that name.
+	Adopt bool `json:"adopt,omitempty"`
+	// KeyID is the ID of the Sentry key to adopt. Requires Adopt.
+	KeyID string `json:"keyId,omitempty"`
+	// Settings of the key in Sentry.
+	Settings ClientKeySettings `json:"settings,omitempty"`
+
+	// Secret configures the Secret holding the DSNs of the key.
+	Secret *ClientKeySecretTemplate `json:"secret,omitempty"`
+	// ConfigMap configures a ConfigMap publishing the public DSN, the CSP
+	// report URI and the loader script URL of the key. No ConfigMap is
+	// written if not set.
+	ConfigMap *ClientKeyConfigMap `json:"configMap,omitempty"`
+
+	// RotationPolicy configures the periodic rotation of the key. Keys can
+	// also be rotated on demand by setting the sentry.sr.github.com/rotate
+	// annotation to a new value.
+	RotationPolicy *ClientKeyRotationPolicy `json:"rotationPolicy,omitempty"`
+
+	// DeletionPolicy of the Sentry key, and of the previous keys still in
+	// their grace period. Defaults to the controller-wide policy.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// ProjectReference references a project either by the name of the Project,
+// in the same namespace, managing it or by its slug.
+type ProjectReference struct {
+	// Name of the Project. The key is not created until the Project is
+	// Ready, and follows the project when its slug changes.
+	Name string `json:"name,omitempty"`
+	// Slug of the project. Ignored if Name is set.
+	Slug string `json:"slug,omitempty"`
+}
+
+// ClientKeySettings are the settings of a key. Settings that are not set are
+// left unmanaged. Settings that are set are reconciled, reverting changes
+// made in the Sentry UI.
+type ClientKeySettings struct {
+	// IsActive enables or disables the key. Events sent with a disabled key
+	// are rejected.
+	IsActive *bool `json:"isActive,omitempty"`
+	// RateLimit limits the number of events accepted with the key.
+	RateLimit *ClientKeyRateLimit `json:"rateLimit,omitempty"`
+	// BrowserSDK configures the browser SDK loader of the key.
+	BrowserSDK *ClientKeyBrowserSDK `json:"browserSdk,omitempty"`
+}
+
+// ClientKeySecretTemplate describes the Secret holding the DSNs of a key.
+type ClientKeySecretTemplate struct {
+	// Name of the Secret. Defaults to the name of the ClientKey.
+	Name string `json:"name,omitempty"`
+	// Labels added to the Secret.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations added to the Secret.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Type of the Secret. Defaults to Opaque.
+	Type corev1.SecretType `json:"type,omitempty"`
+	// Data maps the keys of the Secret to Go templates rendering their value,
+	// e.g. "SENTRY_DSN: '{{ .DSN.Secret }}'". Templates are executed with the
+	// fields DSN.Secret, DSN.Public, DSN.CSP, ID, Project and Organization.
+	// Defaults to the keys dsn.secret, dsn.public and dsn.csp.
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// ClientKeyConfigMap describes the ConfigMap holding the public settings of a
+// key, under the dsn.public, dsn.csp and loader.url keys.
+type ClientKeyConfigMap struct {
+	// Name of the ConfigMap. Defaults to the name of the ClientKey.
+	Name string `json:"name,omitempty"`
+	// Labels added to the ConfigMap.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations added to the ConfigMap.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ClientKeyRotationPolicy configures the rotation of a key. Rotating creates a
+// new key and writes it to the Secret. The previous key is kept active for
+// the grace period, so that running workloads can pick up the new Secret,
+// then deactivated and deleted.
+type ClientKeyRotationPolicy struct {
+	// Period after which the key is rotated, e.g. "720h". Keys are only
+	// rotated on demand if not set.
+	Period *metav1.Duration `json:"period,omitempty"`
+	// GracePeriod during which the previous key is kept active. Defaults to
+	// 24h.
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
+// ClientKeyRateLimit limits the number of events accepted with a key to Count
+// per Window seconds.
+type ClientKeyRateLimit struct {
+	// +kubebuilder:validation:Minimum=0
+	Count int32 `json:"count"`
+	// +kubebuilder:validation:Minimum=1
+	Window int32 `json:"window"`
+}
+
+// ClientKeyBrowserSDK configures the browser SDK loader of a key.
+type ClientKeyBrowserSDK struct {
+	// Version of the SDK served by the loader, e.g. "7.x".
+	Version string `json:"version,omitempty"`
+	// Replay bundles Session Replay.
+	Replay *bool `json:"replay,omitempty"`
+	// Performance bundles performance monitoring.
+	Performance *bool `json:"performance,omitempty"`
+	// Debug bundles the debug build of the SDK.
+	Debug *bool `json:"debug,omitempty"`
+}
+
+// ClientKeyStatus defines the observed state of ClientKey
+type ClientKeyStatus struct {
+	OrganizationSlug string `json:"organizationSlug,omitempty"`
+	ProjectSlug      string `json:"projectSlug,omitempty"`
+	KeyID            string `json:"keyId,omitempty"`
+	// SecretName is the name of the Secret holding the DSNs of the key.
+	SecretName string `json:"secretName,omitempty"`
+	// ConfigMapName is the name of the ConfigMap holding the public settings
+	// of the key.
+	ConfigMapName string `json:"configMapName,omitempty"`
+	// KeyCreationTime is the time the current key was created.
+	KeyCreationTime *metav1.Time `json:"keyCreationTime,omitempty"`
+	// RotationRequest is the value of the sentry.sr.github.com/rotate
+	// annotation the key was last rotated for.
+	RotationRequest string `json:"rotationRequest,omitempty"`
+	// PreviousKeys are the keys replaced by a rotation, oldest first.
+	PreviousKeys []ClientKeyPreviousKey `json:"previousKeys,omitempty"`
+
+	// IsActive, RateLimit and BrowserSDK are the effective settings of the
+	// key in Sentry.
+	IsActive   bool                 `json:"isActive,omitempty"`
+	RateLimit  *ClientKeyRateLimit  `json:"rateLimit,omitempty"`
+	BrowserSDK *ClientKeyBrowserSDK `json:"browserSdk,omitempty"`
+
+	ConditionedStatus `json:",inline"`
+}
+
+// ClientKeyPreviousKey is a key replaced by a rotation.
+type ClientKeyPreviousKey struct {
+	KeyID     string      `json:"keyId"`
+	RotatedAt metav1.Time `json:"rotatedAt"`
+	// ExpiresAt is the time the key is deactivated and deleted.
+	ExpiresAt metav1.Time `json:"expiresAt"`
+	// Deleted is set once the key has been deleted from Sentry.
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClientKey is the Schema for the clientkeys API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=clientkeys,shortName=skey,categories=sentry
+// +kubebuilder:printcolumn:name="Organization",type="string",JSONPath=".status.organizationSlug"
+// +kubebuilder:printcolumn:name="Project",type="string",JSONPath=".status.projectSlug"
+// +kubebuilder:printcolumn:name="Key ID",type="string",JSONPath=".status.keyId"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type ClientKey struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClientKeySpec   `json:"spec,omitempty"`
+	Status ClientKeyStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClientKeyList contains a list of ClientKey
+type ClientKeyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClientKey `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClientKey{}, &ClientKeyList{})
+}
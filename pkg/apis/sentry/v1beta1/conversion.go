@@ -0,0 +1,356 @@
+package v1beta1
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// teamsAnnotation holds, on v1alpha1 Projects, the team references of the
+// v1beta1 version that v1alpha1 cannot represent: additional teams referenced
+// by both name and slug, their order, and empty references.
+const teamsAnnotation = "sentry.sr.github.com/v1beta1-teams"
+
+// ConvertTo converts the Team to the v1alpha1 hub version.
+func (src *Team) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*v1alpha1.Team)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.TeamSpec{
+		OrganizationSlug: src.Spec.OrganizationSlug,
+		Slug:             src.Spec.Slug,
+		Name:             src.Spec.DisplayName,
+		Adopt:            src.Spec.Adopt,
+		DeletionPolicy:   v1alpha1.DeletionPolicy(src.Spec.DeletionPolicy),
+	}
+	dst.Status = v1alpha1.TeamStatus{
+		OrganizationSlug:  src.Status.OrganizationSlug,
+		Slug:              src.Status.Slug,
+		Name:              src.Status.DisplayName,
+		ConditionedStatus: convertStatusTo(src.Status.ConditionedStatus),
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub version to a Team.
+func (dst *Team) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*v1alpha1.Team)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = TeamSpec{
+		OrganizationSlug: src.Spec.OrganizationSlug,
+		Slug:             src.Spec.Slug,
+		DisplayName:      src.Spec.Name,
+		Adopt:            src.Spec.Adopt,
+		DeletionPolicy:   DeletionPolicy(src.Spec.DeletionPolicy),
+	}
+	dst.Status = TeamStatus{
+		OrganizationSlug:  src.Status.OrganizationSlug,
+		Slug:              src.Status.Slug,
+		DisplayName:       src.Status.Name,
+		ConditionedStatus: convertStatusFrom(src.Status.ConditionedStatus),
+	}
+	return nil
+}
+
+// ConvertTo converts the Project to the v1alpha1 hub version. Additional
+// teams referenced by name are converted to TeamRefs and the ones referenced
+// by slug to Teams. Team references that would not convert back as is are
+// also kept in the sentry.sr.github.com/v1beta1-teams annotation.
+func (src *Project) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*v1alpha1.Project)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.ProjectSpec{
+		OrganizationSlug:   src.Spec.OrganizationSlug,
+		Slug:               src.Spec.Slug,
+		Name:               src.Spec.DisplayName,
+		Adopt:              src.Spec.Adopt,
+		Platform:           src.Spec.Settings.Platform,
+		DefaultEnvironment: src.Spec.Settings.DefaultEnvironment,
+		ResolveAge:         src.Spec.Settings.ResolveAge,
+		SubjectPrefix:      src.Spec.Settings.SubjectPrefix,
+		DataScrubber:       src.Spec.Settings.DataScrubber,
+		SensitiveFields:    src.Spec.Settings.SensitiveFields,
+		SafeFields:         src.Spec.Settings.SafeFields,
+		ScrubIPAddresses:   src.Spec.Settings.ScrubIPAddresses,
+		AllowedDomains:     src.Spec.Settings.AllowedDomains,
+		DeletionPolicy:     v1alpha1.DeletionPolicy(src.Spec.DeletionPolicy),
+	}
+	teams := projectTeams{Team: src.Spec.Team, AdditionalTeams: src.Spec.AdditionalTeams}
+	teams.convertTo(&dst.Spec)
+	dst.Annotations = withoutAnnotation(dst.Annotations, teamsAnnotation)
+	if !teams.equal(projectTeamsFrom(&dst.Spec)) {
+		data, err := json.Marshal(teams)
+		if err != nil {
+			return err
+		}
+		dst.Annotations = withAnnotation(dst.Annotations, teamsAnnotation, string(data))
+	}
+	dst.Status = v1alpha1.ProjectStatus{
+		OrganizationSlug:  src.Status.OrganizationSlug,
+		Slug:              src.Status.Slug,
+		Name:              src.Status.DisplayName,
+		TeamSlug:          src.Status.OwnerTeamSlug,
+		Teams:             src.Status.TeamSlugs,
+		ConditionedStatus: convertStatusTo(src.Status.ConditionedStatus),
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub version to a Project. Additional
+// teams referenced by name are listed first, unless the team references saved
+// in the sentry.sr.github.com/v1beta1-teams annotation still match the spec.
+func (dst *Project) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*v1alpha1.Project)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = ProjectSpec{
+		OrganizationSlug: src.Spec.OrganizationSlug,
+		Slug:             src.Spec.Slug,
+		DisplayName:      src.Spec.Name,
+		Adopt:            src.Spec.Adopt,
+		Settings: ProjectSettings{
+			Platform:           src.Spec.Platform,
+			DefaultEnvironment: src.Spec.DefaultEnvironment,
+			ResolveAge:         src.Spec.ResolveAge,
+			SubjectPrefix:      src.Spec.SubjectPrefix,
+			DataScrubber:       src.Spec.DataScrubber,
+			SensitiveFields:    src.Spec.SensitiveFields,
+			SafeFields:         src.Spec.SafeFields,
+			ScrubIPAddresses:   src.Spec.ScrubIPAddresses,
+			AllowedDomains:     src.Spec.AllowedDomains,
+		},
+		DeletionPolicy: DeletionPolicy(src.Spec.DeletionPolicy),
+	}
+	teams := projectTeamsFrom(&src.Spec)
+	if data, ok := src.Annotations[teamsAnnotation]; ok {
+		// The annotation is outdated if the teams were changed through
+		// v1alpha1 since.
+		saved := projectTeams{}
+		if err := json.Unmarshal([]byte(data), &saved); err == nil && saved.matches(&src.Spec) {
+			teams = saved
+		}
+		dst.Annotations = withoutAnnotation(dst.Annotations, teamsAnnotation)
+	}
+	dst.Spec.Team, dst.Spec.AdditionalTeams = teams.Team, teams.AdditionalTeams
+	dst.Status = ProjectStatus{
+		OrganizationSlug:  src.Status.OrganizationSlug,
+		Slug:              src.Status.Slug,
+		DisplayName:       src.Status.Name,
+		OwnerTeamSlug:     src.Status.TeamSlug,
+		TeamSlugs:         src.Status.Teams,
+		ConditionedStatus: convertStatusFrom(src.Status.ConditionedStatus),
+	}
+	return nil
+}
+
+// projectTeams are the team references of a v1beta1 Project.
+type projectTeams struct {
+	Team            *TeamReference  `json:"team,omitempty"`
+	AdditionalTeams []TeamReference `json:"additionalTeams,omitempty"`
+}
+
+// projectTeamsFrom returns the team references of the given v1alpha1 spec.
+func projectTeamsFrom(spec *v1alpha1.ProjectSpec) projectTeams {
+	var teams projectTeams
+	if spec.TeamRef != nil || spec.TeamSlug != "" {
+		teams.Team = &TeamReference{Slug: spec.TeamSlug}
+		if spec.TeamRef != nil {
+			teams.Team.Name = spec.TeamRef.Name
+		}
+	}
+	for _, ref := range spec.TeamRefs {
+		teams.AdditionalTeams = append(teams.AdditionalTeams, TeamReference{Name: ref.Name})
+	}
+	for _, slug := range spec.Teams {
+		teams.AdditionalTeams = append(teams.AdditionalTeams, TeamReference{Slug: slug})
+	}
+	return teams
+}
+
+// convertTo sets the team references of the given v1alpha1 spec.
+func (t projectTeams) convertTo(spec *v1alpha1.ProjectSpec) {
+	if team := t.Team; team != nil {
+		spec.TeamSlug = team.Slug
+		if team.Name != "" {
+			spec.TeamRef = &corev1.LocalObjectReference{Name: team.Name}
+		}
+	}
+	for _, team := range t.AdditionalTeams {
+		if team.Name != "" {
+			spec.TeamRefs = append(spec.TeamRefs, corev1.LocalObjectReference{Name: team.Name})
+		} else {
+			spec.Teams = append(spec.Teams, team.Slug)
+		}
+	}
+}
+
+// matches reports whether t converts to the team references of spec.
+func (t projectTeams) matches(spec *v1alpha1.ProjectSpec) bool {
+	converted := &v1alpha1.ProjectSpec{}
+	t.convertTo(converted)
+	return projectTeamsFrom(converted).equal(projectTeamsFrom(spec))
+}
+
+// equal compares t and other as serialized, so that nil and empty lists are
+// equal.
+func (t projectTeams) equal(other projectTeams) bool {
+	a, errA := json.Marshal(t)
+	b, errB := json.Marshal(other)
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
+
+// withAnnotation returns a copy of annotations with key set to value, leaving
+// the map shared with the converted object untouched.
+func withAnnotation(annotations map[string]string, key, value string) map[string]string {
+	copied := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		copied[k] = v
+	}
+	copied[key] = value
+	return copied
+}
+
+// withoutAnnotation returns a copy of annotations without key, leaving the
+// map shared with the converted object untouched.
+func withoutAnnotation(annotations map[string]string, key string) map[string]string {
+	if _, ok := annotations[key]; !ok {
+		return annotations
+	}
+	copied := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		if k != key {
+			copied[k] = v
+		}
+	}
+	if len(copied) == 0 {
+		return nil
+	}
+	return copied
+}
+
+// ConvertTo converts the ClientKey to the v1alpha1 hub version.
+func (src *ClientKey) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*v1alpha1.ClientKey)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.ClientKeySpec{
+		OrganizationSlug: src.Spec.OrganizationSlug,
+		ProjectSlug:      src.Spec.Project.Slug,
+		Name:             src.Spec.DisplayName,
+		Adopt:            src.Spec.Adopt,
+		KeyID:            src.Spec.KeyID,
+		IsActive:         src.Spec.Settings.IsActive,
+		RateLimit:        (*v1alpha1.ClientKeyRateLimit)(src.Spec.Settings.RateLimit),
+		BrowserSDK:       (*v1alpha1.ClientKeyBrowserSDK)(src.Spec.Settings.BrowserSDK),
+		SecretTemplate:   (*v1alpha1.ClientKeySecretTemplate)(src.Spec.Secret),
+		ConfigMap:        (*v1alpha1.ClientKeyConfigMap)(src.Spec.ConfigMap),
+		RotationPolicy:   (*v1alpha1.ClientKeyRotationPolicy)(src.Spec.RotationPolicy),
+		DeletionPolicy:   v1alpha1.DeletionPolicy(src.Spec.DeletionPolicy),
+	}
+	if src.Spec.Project.Name != "" {
+		dst.Spec.ProjectRef = &corev1.LocalObjectReference{Name: src.Spec.Project.Name}
+	}
+	dst.Status = v1alpha1.ClientKeyStatus{
+		OrganizationSlug:  src.Status.OrganizationSlug,
+		ProjectSlug:       src.Status.ProjectSlug,
+		ID:                src.Status.KeyID,
+		SecretName:        src.Status.SecretName,
+		ConfigMapName:     src.Status.ConfigMapName,
+		KeyCreationTime:   src.Status.KeyCreationTime,
+		RotationRequest:   src.Status.RotationRequest,
+		IsActive:          src.Status.IsActive,
+		RateLimit:         (*v1alpha1.ClientKeyRateLimit)(src.Status.RateLimit),
+		BrowserSDK:        (*v1alpha1.ClientKeyBrowserSDK)(src.Status.BrowserSDK),
+		ConditionedStatus: convertStatusTo(src.Status.ConditionedStatus),
+	}
+	for _, k := range src.Status.PreviousKeys {
+		dst.Status.PreviousKeys = append(dst.Status.PreviousKeys, v1alpha1.ClientKeyPreviousKey{
+			ID:        k.KeyID,
+			RotatedAt: k.RotatedAt,
+			ExpiresAt: k.ExpiresAt,
+			Deleted:   k.Deleted,
+		})
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub version to a ClientKey.
+func (dst *ClientKey) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*v1alpha1.ClientKey)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = ClientKeySpec{
+		OrganizationSlug: src.Spec.OrganizationSlug,
+		Project:          ProjectReference{Slug: src.Spec.ProjectSlug},
+		DisplayName:      src.Spec.Name,
+		Adopt:            src.Spec.Adopt,
+		KeyID:            src.Spec.KeyID,
+		Settings: ClientKeySettings{
+			IsActive:   src.Spec.IsActive,
+			RateLimit:  (*ClientKeyRateLimit)(src.Spec.RateLimit),
+			BrowserSDK: (*ClientKeyBrowserSDK)(src.Spec.BrowserSDK),
+		},
+		Secret:         (*ClientKeySecretTemplate)(src.Spec.SecretTemplate),
+		ConfigMap:      (*ClientKeyConfigMap)(src.Spec.ConfigMap),
+		RotationPolicy: (*ClientKeyRotationPolicy)(src.Spec.RotationPolicy),
+		DeletionPolicy: DeletionPolicy(src.Spec.DeletionPolicy),
+	}
+	if src.Spec.ProjectRef != nil {
+		dst.Spec.Project.Name = src.Spec.ProjectRef.Name
+	}
+	dst.Status = ClientKeyStatus{
+		OrganizationSlug:  src.Status.OrganizationSlug,
+		ProjectSlug:       src.Status.ProjectSlug,
+		KeyID:             src.Status.ID,
+		SecretName:        src.Status.SecretName,
+		ConfigMapName:     src.Status.ConfigMapName,
+		KeyCreationTime:   src.Status.KeyCreationTime,
+		RotationRequest:   src.Status.RotationRequest,
+		IsActive:          src.Status.IsActive,
+		RateLimit:         (*ClientKeyRateLimit)(src.Status.RateLimit),
+		BrowserSDK:        (*ClientKeyBrowserSDK)(src.Status.BrowserSDK),
+		ConditionedStatus: convertStatusFrom(src.Status.ConditionedStatus),
+	}
+	for _, k := range src.Status.PreviousKeys {
+		dst.Status.PreviousKeys = append(dst.Status.PreviousKeys, ClientKeyPreviousKey{
+			KeyID:     k.ID,
+			RotatedAt: k.RotatedAt,
+			ExpiresAt: k.ExpiresAt,
+			Deleted:   k.Deleted,
+		})
+	}
+	return nil
+}
+
+func convertStatusTo(src ConditionedStatus) v1alpha1.ConditionedStatus {
+	dst := v1alpha1.ConditionedStatus{
+		ObservedGeneration: src.ObservedGeneration,
+		LastSyncTime:       src.LastSyncTime,
+	}
+	for _, c := range src.Conditions {
+		dst.Conditions = append(dst.Conditions, v1alpha1.Condition{
+			Type:               v1alpha1.ConditionType(c.Type),
+			Status:             c.Status,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+	return dst
+}
+
+func convertStatusFrom(src v1alpha1.ConditionedStatus) ConditionedStatus {
+	dst := ConditionedStatus{
+		ObservedGeneration: src.ObservedGeneration,
+		LastSyncTime:       src.LastSyncTime,
+	}
+	for _, c := range src.Conditions {
+		dst.Conditions = append(dst.Conditions, Condition{
+			Type:               ConditionType(c.Type),
+			Status:             c.Status,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+	return dst
+}
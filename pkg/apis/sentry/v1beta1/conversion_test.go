@@ -0,0 +1,236 @@
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+func TestConversion(t *testing.T) {
+	now := metav1.NewTime(time.Unix(1571212800, 0))
+	active := true
+	status := v1alpha1.ConditionedStatus{
+		ObservedGeneration: 2,
+		LastSyncTime:       &now,
+		Conditions: []v1alpha1.Condition{{
+			Type:               v1alpha1.ConditionReady,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: now,
+			Reason:             "Synced",
+		}},
+	}
+	meta := metav1.ObjectMeta{Namespace: "testing", Name: "test", Generation: 2}
+
+	for _, tc := range []struct {
+		name  string
+		hub   conversion.Hub
+		spoke conversion.Convertible
+		want  conversion.Convertible
+	}{
+		{
+			name: "team",
+			hub: &v1alpha1.Team{
+				ObjectMeta: meta,
+				Spec:       v1alpha1.TeamSpec{OrganizationSlug: "my-org", Slug: "my-team", Name: "My Team", Adopt: true, DeletionPolicy: v1alpha1.DeletionPolicyRetain},
+				Status:     v1alpha1.TeamStatus{OrganizationSlug: "my-org", Slug: "my-team", Name: "My Team", ConditionedStatus: status},
+			},
+			spoke: &Team{},
+			want: &Team{
+				ObjectMeta: meta,
+				Spec:       TeamSpec{OrganizationSlug: "my-org", Slug: "my-team", DisplayName: "My Team", Adopt: true, DeletionPolicy: DeletionPolicyRetain},
+				Status:     TeamStatus{OrganizationSlug: "my-org", Slug: "my-team", DisplayName: "My Team", ConditionedStatus: convertStatusFrom(status)},
+			},
+		},
+		{
+			name: "project",
+			hub: &v1alpha1.Project{
+				ObjectMeta: meta,
+				Spec: v1alpha1.ProjectSpec{
+					Slug:       "my-proj",
+					TeamRef:    &corev1.LocalObjectReference{Name: "test-team"},
+					TeamRefs:   []corev1.LocalObjectReference{{Name: "other-team"}},
+					Teams:      []string{"ops"},
+					Platform:   "go",
+					ResolveAge: new(int32),
+				},
+				Status: v1alpha1.ProjectStatus{OrganizationSlug: "my-org", Slug: "my-proj", TeamSlug: "my-team", Teams: []string{"my-team", "ops"}},
+			},
+			spoke: &Project{},
+			want: &Project{
+				ObjectMeta: meta,
+				Spec: ProjectSpec{
+					Slug:            "my-proj",
+					Team:            &TeamReference{Name: "test-team"},
+					AdditionalTeams: []TeamReference{{Name: "other-team"}, {Slug: "ops"}},
+					Settings:        ProjectSettings{Platform: "go", ResolveAge: new(int32)},
+				},
+				Status: ProjectStatus{OrganizationSlug: "my-org", Slug: "my-proj", OwnerTeamSlug: "my-team", TeamSlugs: []string{"my-team", "ops"}},
+			},
+		},
+		{
+			name: "project with team slug",
+			hub: &v1alpha1.Project{
+				ObjectMeta: meta,
+				Spec:       v1alpha1.ProjectSpec{OrganizationSlug: "my-org", Slug: "my-proj", TeamSlug: "my-team"},
+			},
+			spoke: &Project{},
+			want: &Project{
+				ObjectMeta: meta,
+				Spec:       ProjectSpec{OrganizationSlug: "my-org", Slug: "my-proj", Team: &TeamReference{Slug: "my-team"}},
+			},
+		},
+		{
+			name: "client key",
+			hub: &v1alpha1.ClientKey{
+				ObjectMeta: meta,
+				Spec: v1alpha1.ClientKeySpec{
+					ProjectRef:     &corev1.LocalObjectReference{Name: "test-proj"},
+					Name:           "My Key",
+					IsActive:       &active,
+					RateLimit:      &v1alpha1.ClientKeyRateLimit{Count: 10, Window: 60},
+					SecretTemplate: &v1alpha1.ClientKeySecretTemplate{Name: "dsn", Data: map[string]string{"SENTRY_DSN": "{{ .DSN.Secret }}"}},
+					RotationPolicy: &v1alpha1.ClientKeyRotationPolicy{Period: &metav1.Duration{Duration: time.Hour}},
+				},
+				Status: v1alpha1.ClientKeyStatus{
+					OrganizationSlug: "my-org",
+					ProjectSlug:      "my-proj",
+					ID:               "2",
+					SecretName:       "dsn",
+					PreviousKeys:     []v1alpha1.ClientKeyPreviousKey{{ID: "1", RotatedAt: now, ExpiresAt: now}},
+					IsActive:         true,
+				},
+			},
+			spoke: &ClientKey{},
+			want: &ClientKey{
+				ObjectMeta: meta,
+				Spec: ClientKeySpec{
+					Project:     ProjectReference{Name: "test-proj"},
+					DisplayName: "My Key",
+					Settings: ClientKeySettings{
+						IsActive:  &active,
+						RateLimit: &ClientKeyRateLimit{Count: 10, Window: 60},
+					},
+					Secret:         &ClientKeySecretTemplate{Name: "dsn", Data: map[string]string{"SENTRY_DSN": "{{ .DSN.Secret }}"}},
+					RotationPolicy: &ClientKeyRotationPolicy{Period: &metav1.Duration{Duration: time.Hour}},
+				},
+				Status: ClientKeyStatus{
+					OrganizationSlug: "my-org",
+					ProjectSlug:      "my-proj",
+					KeyID:            "2",
+					SecretName:       "dsn",
+					PreviousKeys:     []ClientKeyPreviousKey{{KeyID: "1", RotatedAt: now, ExpiresAt: now}},
+					IsActive:         true,
+				},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.spoke.ConvertFrom(tc.hub); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tc.spoke, tc.want) {
+				t.Errorf("want %+v, got: %+v", tc.want, tc.spoke)
+			}
+
+			// Converting back to the hub must not lose any field.
+			hub := tc.hub.DeepCopyObject().(conversion.Hub)
+			reflect.ValueOf(hub).Elem().Set(reflect.Zero(reflect.TypeOf(hub).Elem()))
+			if err := tc.spoke.ConvertTo(hub); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(hub, tc.hub) {
+				t.Errorf("want round trip to return %+v, got: %+v", tc.hub, hub)
+			}
+		})
+	}
+}
+
+func TestProjectTeamsRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		team           *TeamReference
+		teams          []TeamReference
+		wantAnnotation bool
+	}{
+		{
+			name:           "name and slug",
+			team:           &TeamReference{Name: "test-team", Slug: "my-team"},
+			teams:          []TeamReference{{Name: "other-team", Slug: "other"}},
+			wantAnnotation: true,
+		},
+		{
+			name:           "slugs before names",
+			team:           &TeamReference{Slug: "my-team"},
+			teams:          []TeamReference{{Slug: "ops"}, {Name: "other-team"}, {Slug: "dev"}},
+			wantAnnotation: true,
+		},
+		{
+			name:           "empty team",
+			team:           &TeamReference{},
+			wantAnnotation: true,
+		},
+		{
+			name:  "names before slugs",
+			team:  &TeamReference{Name: "test-team"},
+			teams: []TeamReference{{Name: "other-team"}, {Slug: "ops"}},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			src := &Project{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "test", Annotations: map[string]string{"a": "b"}},
+				Spec:       ProjectSpec{Slug: "my-proj", Team: tc.team, AdditionalTeams: tc.teams},
+			}
+			hub := &v1alpha1.Project{}
+			if err := src.DeepCopy().ConvertTo(hub); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := hub.Annotations[teamsAnnotation]; ok != tc.wantAnnotation {
+				t.Errorf("want annotation %s set %v, got: %v", teamsAnnotation, tc.wantAnnotation, hub.Annotations)
+			}
+
+			dst := &Project{}
+			if err := dst.ConvertFrom(hub); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(dst, src) {
+				t.Errorf("want round trip to return %+v, got: %+v", src, dst)
+			}
+		})
+	}
+}
+
+func TestProjectTeamsAnnotationOutdated(t *testing.T) {
+	src := &Project{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "test"},
+		Spec: ProjectSpec{
+			Slug:            "my-proj",
+			Team:            &TeamReference{Slug: "my-team"},
+			AdditionalTeams: []TeamReference{{Name: "other-team", Slug: "other"}},
+		},
+	}
+	hub := &v1alpha1.Project{}
+	if err := src.ConvertTo(hub); err != nil {
+		t.Fatal(err)
+	}
+
+	// Teams changed through v1alpha1 take precedence over the annotation.
+	hub.Spec.TeamRefs = nil
+	hub.Spec.Teams = []string{"ops"}
+	dst := &Project{}
+	if err := dst.ConvertFrom(hub); err != nil {
+		t.Fatal(err)
+	}
+	if want := []TeamReference{{Slug: "ops"}}; !reflect.DeepEqual(dst.Spec.AdditionalTeams, want) {
+		t.Errorf("want additional teams %+v, got: %+v", want, dst.Spec.AdditionalTeams)
+	}
+	if _, ok := dst.Annotations[teamsAnnotation]; ok {
+		t.Errorf("want annotation removed, got: %v", dst.Annotations)
+	}
+}
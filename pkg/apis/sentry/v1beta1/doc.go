@@ -0,0 +1,7 @@
+// Package v1beta1 contains API Schema definitions for the sentry v1beta1 API group
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=package,register
+// +k8s:conversion-gen=github.com/sr/kube-sentry-controller/pkg/apis/sentry
+// +k8s:defaulter-gen=TypeMeta
+// +groupName=sentry.sr.github.com
+package v1beta1
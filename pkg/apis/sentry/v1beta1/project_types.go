@@ -0,0 +1,112 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProjectSpec defines the desired state of Project
+type ProjectSpec struct {
+	// OrganizationSlug is the slug of the organization of the project.
+	// Defaults to the organization of the Team owning the project.
+	OrganizationSlug string `json:"organizationSlug,omitempty"`
+	// Slug of the project.
+	Slug string `json:"slug"`
+	// DisplayName of the project. Defaults to the slug.
+	DisplayName string `json:"displayName,omitempty"`
+	// Team owning the project.
+	Team *TeamReference `json:"team,omitempty"`
+	// AdditionalTeams the project belongs to.
+	AdditionalTeams []TeamReference `json:"additionalTeams,omitempty"`
+	// Adopt an existing Sentry project with the same slug instead of failing
+	// to create it. Its settings and teams are reconciled with the spec from
+	// then on.
+	Adopt bool `json:"adopt,omitempty"`
+	// Settings of the project in Sentry.
+	Settings ProjectSettings `json:"settings,omitempty"`
+
+	// DeletionPolicy of the Sentry project. Deleting a project also deletes
+	// its issues and events; use Retain to keep them. Defaults to the
+	// controller-wide policy.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// TeamReference references a team either by the name of the Team, in the
+// same namespace, managing it or by its slug.
+type TeamReference struct {
+	// Name of the Team. The project is not created until the Team is Ready.
+	Name string `json:"name,omitempty"`
+	// Slug of the team. Ignored if Name is set.
+	Slug string `json:"slug,omitempty"`
+}
+
+// ProjectSettings are the settings of a project. Settings that are not set
+// are left unmanaged. Settings that are set are reconciled, reverting changes
+// made in the Sentry UI.
+type ProjectSettings struct {
+	// Platform of the project, e.g. "go" or "javascript-react".
+	Platform string `json:"platform,omitempty"`
+	// DefaultEnvironment selected in the Sentry UI.
+	DefaultEnvironment string `json:"defaultEnvironment,omitempty"`
+	// ResolveAge is the number of hours after which issues are automatically
+	// resolved. 0 disables automatic resolution.
+	// +kubebuilder:validation:Minimum=0
+	ResolveAge *int32 `json:"resolveAge,omitempty"`
+	// SubjectPrefix of email notifications.
+	SubjectPrefix string `json:"subjectPrefix,omitempty"`
+	// DataScrubber enables server-side scrubbing of sensitive data.
+	DataScrubber *bool `json:"dataScrubber,omitempty"`
+	// SensitiveFields are additional field names to scrub.
+	SensitiveFields []string `json:"sensitiveFields,omitempty"`
+	// SafeFields are field names never to scrub.
+	SafeFields []string `json:"safeFields,omitempty"`
+	// ScrubIPAddresses prevents IP addresses from being stored.
+	ScrubIPAddresses *bool `json:"scrubIPAddresses,omitempty"`
+	// AllowedDomains are the origins allowed to submit events.
+	AllowedDomains []string `json:"allowedDomains,omitempty"`
+}
+
+// ProjectStatus defines the observed state of Project
+type ProjectStatus struct {
+	OrganizationSlug string `json:"organizationSlug,omitempty"`
+	Slug             string `json:"slug,omitempty"`
+	DisplayName      string `json:"displayName,omitempty"`
+	// OwnerTeamSlug is the slug of the team owning the project.
+	OwnerTeamSlug string `json:"ownerTeamSlug,omitempty"`
+	// TeamSlugs are the slugs of all the teams the project belongs to.
+	TeamSlugs []string `json:"teamSlugs,omitempty"`
+
+	ConditionedStatus `json:",inline"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Project is the Schema for the projects API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=projects,shortName=sproj,categories=sentry
+// +kubebuilder:printcolumn:name="Organization",type="string",JSONPath=".status.organizationSlug"
+// +kubebuilder:printcolumn:name="Slug",type="string",JSONPath=".status.slug"
+// +kubebuilder:printcolumn:name="Team",type="string",JSONPath=".status.ownerTeamSlug"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type Project struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProjectSpec   `json:"spec,omitempty"`
+	Status ProjectStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ProjectList contains a list of Project
+type ProjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Project `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Project{}, &ProjectList{})
+}
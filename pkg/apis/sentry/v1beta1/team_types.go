@@ -0,0 +1,63 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TeamSpec defines the desired state of Team
+type TeamSpec struct {
+	// OrganizationSlug is the slug of the organization of the team.
+	OrganizationSlug string `json:"organizationSlug"`
+	// Slug of the team.
+	Slug string `json:"slug"`
+	// DisplayName of the team. Defaults to the slug.
+	DisplayName string `json:"displayName,omitempty"`
+	// Adopt an existing Sentry team with the same slug instead of failing to
+	// create it. The team is managed by the controller from then on.
+	Adopt bool `json:"adopt,omitempty"`
+
+	// DeletionPolicy of the Sentry team. Defaults to the policy the controller
+	// is configured with, Delete unless set otherwise.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// TeamStatus defines the observed state of Team
+type TeamStatus struct {
+	OrganizationSlug string `json:"organizationSlug,omitempty"`
+	Slug             string `json:"slug,omitempty"`
+	DisplayName      string `json:"displayName,omitempty"`
+
+	ConditionedStatus `json:",inline"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Team is the Schema for the teams API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=teams,shortName=stm,categories=sentry
+// +kubebuilder:printcolumn:name="Organization",type="string",JSONPath=".status.organizationSlug"
+// +kubebuilder:printcolumn:name="Slug",type="string",JSONPath=".status.slug"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type Team struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TeamSpec   `json:"spec,omitempty"`
+	Status TeamStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TeamList contains a list of Team
+type TeamList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Team `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Team{}, &TeamList{})
+}
@@ -0,0 +1,101 @@
+package sentrycontroller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	"github.com/sr/kube-sentry-controller/pkg/sentry"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const reasonAdoptionRefused = "AdoptionRefused"
+
+// adoptionRefusedError is returned when the Sentry object described by an
+// object already exists but cannot be adopted, either because the object does
+// not allow it or because the existing object is ambiguous.
+type adoptionRefusedError struct {
+	kind   string
+	name   string
+	reason string
+}
+
+func (e *adoptionRefusedError) Error() string {
+	return fmt.Sprintf("cannot adopt sentry %s %s: %s", e.kind, e.name, e.reason)
+}
+
+func isAdoptionRefused(err error) bool {
+	_, ok := errors.Cause(err).(*adoptionRefusedError)
+	return ok
+}
+
+// adoptTeam records the existing Sentry team with the slug of instance in its
+// status. It does nothing if there is no such team.
+func (r *reconcilerSet) adoptTeam(ctx context.Context, cli sentry.Client, instance *sentryv1alpha1.Team) error {
+	team, _, err := cli.GetTeam(ctx, instance.Spec.OrganizationSlug, instance.Spec.Slug)
+	if sentry.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return r.sentryError(instance, err, "failed to get team %s", instance.Spec.Slug)
+	}
+	instance.Status.Slug = team.Slug
+	instance.Status.Name = team.Name
+	instance.Status.OrganizationSlug = instance.Spec.OrganizationSlug
+	r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonAdopted, "Adopted Sentry team %s in organization %s", team.Slug, instance.Status.OrganizationSlug)
+	return nil
+}
+
+// adoptProject records the existing Sentry project with the slug of instance
+// in its status. It does nothing if there is no such project.
+func (r *reconcilerSet) adoptProject(ctx context.Context, cli sentry.Client, instance *sentryv1alpha1.Project, org string) error {
+	proj, _, err := cli.GetProject(ctx, org, instance.Spec.Slug)
+	if sentry.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return r.sentryError(instance, err, "failed to get project %s", instance.Spec.Slug)
+	}
+	instance.Status.Slug = proj.Slug
+	instance.Status.OrganizationSlug = org
+	r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonAdopted, "Adopted Sentry project %s in organization %s", proj.Slug, org)
+	return nil
+}
+
+// adoptClientKey records the existing Sentry key selected by the spec of
+// instance in its status. Without a KeyID, it does nothing if no key has the
+// name of instance.
+func (r *reconcilerSet) adoptClientKey(ctx context.Context, cli sentry.Client, instance *sentryv1alpha1.ClientKey, org, project string) error {
+	keys, _, err := cli.GetClientKeys(ctx, org, project)
+	if err != nil {
+		return r.sentryError(instance, err, "failed to get client keys for project %s", project)
+	}
+
+	var found []*sentry.ClientKey
+	for _, k := range keys {
+		if (instance.Spec.KeyID != "" && k.ID == instance.Spec.KeyID) || (instance.Spec.KeyID == "" && k.Name == instance.Spec.Name) {
+			found = append(found, k)
+		}
+	}
+	switch {
+	case len(found) == 0 && instance.Spec.KeyID != "":
+		r.recorder.Eventf(instance, corev1.EventTypeWarning, eventReasonKeyNotFound, "Sentry client key %s not found in project %s", instance.Spec.KeyID, project)
+		return errKeyNotFound
+	case len(found) == 0:
+		return nil
+	case len(found) > 1:
+		return &adoptionRefusedError{
+			kind:   "client key",
+			name:   fmt.Sprintf("%q", instance.Spec.Name),
+			reason: fmt.Sprintf("project %s has %d keys with this name, set spec.keyId to select one", project, len(found)),
+		}
+	}
+
+	instance.Status.ID = found[0].ID
+	instance.Status.ProjectSlug = project
+	instance.Status.OrganizationSlug = org
+	instance.Status.RotationRequest = instance.Annotations[rotateAnnotation]
+	r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonAdopted, "Adopted Sentry client key %s of project %s", found[0].ID, project)
+	return nil
+}
@@ -0,0 +1,65 @@
+package sentrycontroller
+
+import (
+	"sync"
+
+	"github.com/sr/kube-sentry-controller/pkg/sentry"
+)
+
+// clientCache holds the sentry API client of every Organization, so that the
+// state kept by a client, e.g. the rate limit window reported by the Sentry
+// API, outlives a single reconcile. The zero value is ready to use.
+type clientCache struct {
+	mu      sync.Mutex
+	clients map[string]*cachedClient // by Organization name
+}
+
+type cachedClient struct {
+	slug     string
+	token    string
+	endpoint string
+	client   sentry.Client
+}
+
+// lookup returns the cached client of the organization with the given slug, or
+// nil if there is none.
+func (c *clientCache) lookup(slug string) sentry.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cc := range c.clients {
+		if cc.slug == slug {
+			return cc.client
+		}
+	}
+	return nil
+}
+
+// get returns the cached client of the Organization with the given name. A
+// new client is created with newSentry, and cached, if there is none yet or if
+// its token or endpoint changed.
+func (c *clientCache) get(name, slug, token, endpoint string, newSentry func(token, endpoint string) (sentry.Client, error)) (sentry.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cc, ok := c.clients[name]; ok && cc.token == token && cc.endpoint == endpoint {
+		cc.slug = slug
+		return cc.client, nil
+	}
+
+	cli, err := newSentry(token, endpoint)
+	if err != nil {
+		delete(c.clients, name)
+		return nil, err
+	}
+	if c.clients == nil {
+		c.clients = make(map[string]*cachedClient)
+	}
+	c.clients[name] = &cachedClient{slug: slug, token: token, endpoint: endpoint, client: cli}
+	return cli, nil
+}
+
+// forget drops the cached client of the Organization with the given name.
+func (c *clientCache) forget(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.clients, name)
+}
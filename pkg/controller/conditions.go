@@ -0,0 +1,105 @@
+package sentrycontroller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	"github.com/sr/kube-sentry-controller/pkg/sentry"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	reasonSynced         = "Synced"
+	reasonReconcileError = "ReconcileError"
+	reasonSentryAPIError = "SentryAPIError"
+
+	reasonInvalidSecretTemplate = "InvalidSecretTemplate"
+)
+
+// setSyncConditions records the outcome of a reconcile attempt on status. Once
+// the Sentry object has been created, the Ready condition is only flipped to
+// False by permanent errors, so that a transient API failure does not mark an
+// existing object as unavailable.
+func setSyncConditions(status *sentryv1alpha1.ConditionedStatus, generation int64, created bool, err error) {
+	now := metav1.Now()
+
+	if err == nil {
+		status.ObservedGeneration = generation
+		status.LastSyncTime = &now
+		status.SetCondition(sentryv1alpha1.Condition{
+			Type:               sentryv1alpha1.ConditionSynced,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: now,
+			Reason:             reasonSynced,
+		})
+		status.SetCondition(sentryv1alpha1.Condition{
+			Type:               sentryv1alpha1.ConditionReady,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: now,
+			Reason:             reasonSynced,
+		})
+		return
+	}
+
+	cond := sentryv1alpha1.Condition{
+		Type:               sentryv1alpha1.ConditionSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: now,
+		Reason:             errorReason(err),
+		Message:            err.Error(),
+	}
+	status.SetCondition(cond)
+
+	if !created || !isTransient(err) {
+		cond.Type = sentryv1alpha1.ConditionReady
+		status.SetCondition(cond)
+	}
+}
+
+// isTransient reports whether err is expected to go away on a later attempt.
+// Sentry API errors are classified by the sentry package. Other errors, e.g.
+// from the Kubernetes API, are assumed to be transient.
+func isTransient(err error) bool {
+	cause := errors.Cause(err)
+	if cause == errKeyNotFound || isAdoptionRefused(err) || isNotControlled(err) || isInvalidTemplate(err) {
+		return false
+	}
+	if _, ok := cause.(*sentry.ErrorResponse); ok {
+		return sentry.IsTransient(err)
+	}
+	return true
+}
+
+// errorReason returns a CamelCase condition reason for err. Errors returned by
+// the Sentry API are named after their HTTP status, e.g. "Forbidden".
+func errorReason(err error) string {
+	if errors.Cause(err) == errKeyNotFound {
+		return eventReasonKeyNotFound
+	}
+	if isParentNotReady(err) {
+		return reasonParentNotReady
+	}
+	if isAdoptionRefused(err) {
+		return reasonAdoptionRefused
+	}
+	if isNotControlled(err) {
+		return eventReasonResourceExists
+	}
+	if isInvalidTemplate(err) {
+		return reasonInvalidSecretTemplate
+	}
+	e, ok := errors.Cause(err).(*sentry.ErrorResponse)
+	if !ok {
+		return reasonReconcileError
+	}
+	if e.Response == nil {
+		return reasonSentryAPIError
+	}
+	if text := http.StatusText(e.Response.StatusCode); text != "" {
+		return strings.Replace(text, " ", "", -1)
+	}
+	return reasonSentryAPIError
+}
@@ -0,0 +1,94 @@
+package sentrycontroller
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	"github.com/sr/kube-sentry-controller/pkg/sentry"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// syncConfigMap creates or updates the ConfigMap publishing the public
+// settings of key, if instance asks for one. The ConfigMap previously created
+// for instance is deleted if it was renamed or is no longer wanted. An existing
+// ConfigMap that is not controlled by instance is left untouched.
+func (r *reconcilerSet) syncConfigMap(ctx context.Context, instance *sentryv1alpha1.ClientKey, key *sentry.ClientKey) error {
+	spec := instance.Spec.ConfigMap
+
+	var name string
+	if spec != nil {
+		name = spec.Name
+		if name == "" {
+			name = instance.Name
+		}
+	}
+
+	found := &corev1.ConfigMap{}
+	exists := false
+	if spec != nil {
+		err := r.kube.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: name}, found)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		exists = err == nil
+		if exists && !metav1.IsControlledBy(found, instance) {
+			return r.notControlled(instance, "configmap", found.Name)
+		}
+	}
+
+	if old := instance.Status.ConfigMapName; old != "" && old != name {
+		if err := r.deleteControlled(ctx, instance, &corev1.ConfigMap{}, old); err != nil {
+			return err
+		}
+	}
+	instance.Status.ConfigMapName = name
+	if spec == nil {
+		return nil
+	}
+
+	var dsn sentry.ClientKeyDSN
+	if key.DSN != nil {
+		dsn = *key.DSN
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   instance.Namespace,
+			Name:        name,
+			Labels:      spec.Labels,
+			Annotations: spec.Annotations,
+		},
+		Data: map[string]string{
+			"dsn.public": dsn.Public,
+			"dsn.csp":    dsn.CSP,
+			"loader.url": dsn.CDN,
+		},
+	}
+	if err := controllerutil.SetControllerReference(instance, cm, r.scheme); err != nil {
+		return errors.Wrap(err, "failed to set controller reference on configmap")
+	}
+
+	if !exists {
+		return errors.Wrapf(r.kube.Create(ctx, cm), "failed to create configmap")
+	}
+
+	// Labels and annotations set by others are preserved.
+	base := found.DeepCopy()
+	changed := !reflect.DeepEqual(cm.Data, found.Data)
+	found.Data = cm.Data
+	if mergeStrings(&found.Labels, cm.Labels) {
+		changed = true
+	}
+	if mergeStrings(&found.Annotations, cm.Annotations) {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return errors.Wrapf(r.kube.Patch(ctx, found, client.MergeFrom(base)), "failed to update configmap")
+}
@@ -1,65 +1,228 @@
 package sentrycontroller
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-logr/logr"
 	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
 	"github.com/sr/kube-sentry-controller/pkg/sentry"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+// Options configures the sentry controllers.
+type Options struct {
+	// Sentry is the client used to manage the organizations that are not
+	// described by an Organization object. Optional.
+	Sentry sentry.Client
+
+	// NewSentry returns a client authenticated with the given API token. It is
+	// used to manage the organizations described by an Organization object,
+	// with the token and endpoint stored in the Secret it references. An empty
+	// endpoint selects the default Sentry API endpoint.
+	NewSentry func(token, endpoint string) (sentry.Client, error)
+
+	// Timeout for a single reconcilation attempt.
+	Timeout time.Duration
+
+	// DeletionPolicy applied to objects that do not set one in their spec.
+	// Defaults to Delete.
+	DeletionPolicy sentryv1alpha1.DeletionPolicy
+
+	// ResyncPeriod after which objects are synced again, reverting changes
+	// made in Sentry. Objects can override it with the
+	// sentry.sr.github.com/resync-period annotation. Objects are only synced
+	// when they change if zero.
+	ResyncPeriod time.Duration
+
+	// Selector restricts the Teams, Projects and ClientKeys reconciled to
+	// those matching it, e.g. to shard them between several controllers.
+	// It only filters watch events: the cache of the manager still lists and
+	// watches all the objects, which the reconcilers need to resolve
+	// references across shards. Combine with a cache restricted to some
+	// namespaces, see NewCache, to restrict the objects cached, including the
+	// Secrets and ConfigMaps. Optional.
+	Selector labels.Selector
+
+	// InFlight, if set, counts the reconciles in progress, e.g. to let them
+	// complete before exiting. Optional.
+	InFlight *InFlight
+}
+
 // Add initializes the sentry controller, sets up watches, and adds it to manager.
-func Add(mgr manager.Manager, logger logr.Logger, sentry sentry.Client, timeout time.Duration) error {
+func Add(mgr manager.Manager, logger logr.Logger, opts Options) error {
 	r := &reconcilerSet{
-		scheme:  mgr.GetScheme(),
-		kube:    mgr.GetClient(),
-		sentry:  sentry,
-		timeout: timeout,
+		scheme:    mgr.GetScheme(),
+		kube:      mgr.GetClient(),
+		sentry:    opts.Sentry,
+		newSentry: opts.NewSentry,
+		recorder:  mgr.GetEventRecorderFor("kube-sentry-controller"),
+		timeout:   opts.Timeout,
+
+		deletionPolicy: opts.DeletionPolicy,
+		resyncPeriod:   opts.ResyncPeriod,
+		selector:       opts.Selector,
+	}
+
+	if err := registerObjectsCollector(metrics.Registry, &objectsCollector{kube: mgr.GetClient(), selector: opts.Selector}); err != nil {
+		return err
 	}
 
-	c, err := controller.New("sentry-team", mgr, controller.Options{
-		Reconciler: reconcile.Func(r.Team),
+	c, err := controller.New("sentry-organization", mgr, controller.Options{
+		Reconciler: opts.InFlight.track(r.Organization),
 	})
 	if err != nil {
 		return err
 	}
-	err = c.Watch(&source.Kind{Type: &sentryv1alpha1.Team{}}, &handler.EnqueueRequestForObject{})
+	err = c.Watch(&source.Kind{Type: &sentryv1alpha1.Organization{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+	err = c.Watch(
+		&source.Kind{Type: &corev1.Secret{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+				return organizationsForSecret(mgr.GetClient(), o)
+			}),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	c, err = controller.New("sentry-team", mgr, controller.Options{
+		Reconciler: opts.InFlight.track(r.Team),
+	})
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &sentryv1alpha1.Team{}}, &handler.EnqueueRequestForObject{}, selectorPredicate(opts.Selector))
 	if err != nil {
 		return err
 	}
 
 	c, err = controller.New("sentry-project", mgr, controller.Options{
-		Reconciler: reconcile.Func(r.Project),
+		Reconciler: opts.InFlight.track(r.Project),
 	})
 	if err != nil {
 		return err
 	}
-	err = c.Watch(&source.Kind{Type: &sentryv1alpha1.Project{}}, &handler.EnqueueRequestForObject{})
+	err = c.Watch(&source.Kind{Type: &sentryv1alpha1.Project{}}, &handler.EnqueueRequestForObject{}, selectorPredicate(opts.Selector))
+	if err != nil {
+		return err
+	}
+	err = c.Watch(
+		&source.Kind{Type: &sentryv1alpha1.Team{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+				return projectsForTeam(mgr.GetClient(), o)
+			}),
+		},
+	)
 	if err != nil {
 		return err
 	}
 
 	c, err = controller.New("sentry-clientkey", mgr, controller.Options{
-		Reconciler: reconcile.Func(r.ClientKey),
+		Reconciler: opts.InFlight.track(r.ClientKey),
 	})
 	if err != nil {
 		return err
 	}
-	err = c.Watch(&source.Kind{Type: &sentryv1alpha1.ClientKey{}}, &handler.EnqueueRequestForObject{})
+	err = c.Watch(&source.Kind{Type: &sentryv1alpha1.ClientKey{}}, &handler.EnqueueRequestForObject{}, selectorPredicate(opts.Selector))
 	if err != nil {
 		return err
 	}
-	return c.Watch(
+	err = c.Watch(
+		&source.Kind{Type: &sentryv1alpha1.Project{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+				return clientKeysForProject(mgr.GetClient(), o)
+			}),
+		},
+	)
+	if err != nil {
+		return err
+	}
+	err = c.Watch(
 		&source.Kind{Type: &corev1.Secret{}},
 		&handler.EnqueueRequestForOwner{
 			IsController: true,
 			OwnerType:    &sentryv1alpha1.ClientKey{},
 		},
 	)
+	if err != nil {
+		return err
+	}
+	return c.Watch(
+		&source.Kind{Type: &corev1.ConfigMap{}},
+		&handler.EnqueueRequestForOwner{
+			IsController: true,
+			OwnerType:    &sentryv1alpha1.ClientKey{},
+		},
+	)
+}
+
+// organizationsForSecret returns a request for every Organization whose API
+// credentials are stored in the given Secret.
+func organizationsForSecret(kube client.Client, o handler.MapObject) []reconcile.Request {
+	orgs := &sentryv1alpha1.OrganizationList{}
+	if err := kube.List(context.Background(), orgs); err != nil {
+		return nil
+	}
+	var reqs []reconcile.Request
+	for _, org := range orgs.Items {
+		ref := org.Spec.SecretRef
+		if ref.Namespace == o.Meta.GetNamespace() && ref.Name == o.Meta.GetName() {
+			reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKey{Name: org.Name}})
+		}
+	}
+	return reqs
+}
+
+// projectsForTeam returns a request for every Project referencing the given
+// Team with its TeamRef or TeamRefs.
+func projectsForTeam(kube client.Client, o handler.MapObject) []reconcile.Request {
+	projects := &sentryv1alpha1.ProjectList{}
+	if err := kube.List(context.Background(), projects, client.InNamespace(o.Meta.GetNamespace())); err != nil {
+		return nil
+	}
+	var reqs []reconcile.Request
+	for _, p := range projects.Items {
+		refs := p.Spec.TeamRefs
+		if p.Spec.TeamRef != nil {
+			refs = append(refs, *p.Spec.TeamRef)
+		}
+		for _, ref := range refs {
+			if ref.Name == o.Meta.GetName() {
+				reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKey{Namespace: p.Namespace, Name: p.Name}})
+				break
+			}
+		}
+	}
+	return reqs
+}
+
+// clientKeysForProject returns a request for every ClientKey referencing the
+// given Project with its ProjectRef.
+func clientKeysForProject(kube client.Client, o handler.MapObject) []reconcile.Request {
+	keys := &sentryv1alpha1.ClientKeyList{}
+	if err := kube.List(context.Background(), keys, client.InNamespace(o.Meta.GetNamespace())); err != nil {
+		return nil
+	}
+	var reqs []reconcile.Request
+	for _, k := range keys.Items {
+		if ref := k.Spec.ProjectRef; ref != nil && ref.Name == o.Meta.GetName() {
+			reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKey{Namespace: k.Namespace, Name: k.Name}})
+		}
+	}
+	return reqs
 }
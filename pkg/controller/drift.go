@@ -0,0 +1,92 @@
+package sentrycontroller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// resyncAnnotation overrides the resync period of an object, e.g. "5m". A
+// period of "0" disables periodic resyncs of the object.
+const resyncAnnotation = "sentry.sr.github.com/resync-period"
+
+const (
+	reasonDriftCorrected = "DriftCorrected"
+	reasonNoDrift        = "NoDrift"
+)
+
+// drift collects the fields of a Sentry object that were changed outside of
+// the controller and reverted by a sync. Changes applied because the spec
+// itself changed since the last successful sync are not drift.
+type drift struct {
+	detect bool
+	fields []string
+}
+
+func newDrift(status *sentryv1alpha1.ConditionedStatus, generation int64) *drift {
+	return &drift{detect: status.LastSyncTime != nil && status.ObservedGeneration == generation}
+}
+
+func (d *drift) add(fields ...string) {
+	if d.detect {
+		d.fields = append(d.fields, fields...)
+	}
+}
+
+// recordDrift sets the Drifted condition after a successful sync, and records
+// an event and a metric if drift was corrected. The condition is only added
+// once an object has been synced before, as there is nothing to drift from
+// until then.
+func (r *reconcilerSet) recordDrift(obj runtime.Object, status *sentryv1alpha1.ConditionedStatus, kind string, d *drift) {
+	now := metav1.Now()
+
+	if len(d.fields) == 0 {
+		if !d.detect && status.GetCondition(sentryv1alpha1.ConditionDrifted) == nil {
+			return
+		}
+		status.SetCondition(sentryv1alpha1.Condition{
+			Type:               sentryv1alpha1.ConditionDrifted,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: now,
+			Reason:             reasonNoDrift,
+		})
+		return
+	}
+
+	msg := fmt.Sprintf("Reverted changes to %s made outside of the controller", strings.Join(d.fields, ", "))
+	status.SetCondition(sentryv1alpha1.Condition{
+		Type:               sentryv1alpha1.ConditionDrifted,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: now,
+		Reason:             reasonDriftCorrected,
+		Message:            msg,
+	})
+	r.recorder.Event(obj, corev1.EventTypeNormal, eventReasonDriftCorrected, msg)
+	driftCorrections.WithLabelValues(kind).Inc()
+}
+
+// resyncAfter returns the duration after which obj is synced again to detect
+// drift, or 0 if it is not resynced periodically. Invalid annotation values
+// are ignored.
+func (r *reconcilerSet) resyncAfter(obj metav1.Object) time.Duration {
+	if v, ok := obj.GetAnnotations()[resyncAnnotation]; ok {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return r.resyncPeriod
+}
+
+// minRequeue returns the shortest of the given durations that is not 0, or 0
+// if they all are.
+func minRequeue(a, b time.Duration) time.Duration {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,36 @@
+package sentrycontroller
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Reasons of the events recorded on Sentry objects.
+const (
+	eventReasonCreated        = "Created"
+	eventReasonAdopted        = "Adopted"
+	eventReasonRenamed        = "Renamed"
+	eventReasonUpdated        = "Updated"
+	eventReasonDeleted        = "Deleted"
+	eventReasonOrphaned       = "Orphaned"
+	eventReasonDriftCorrected = "DriftCorrected"
+	eventReasonRotated        = "Rotated"
+	eventReasonTeamAdded      = "TeamAdded"
+	eventReasonTeamRemoved    = "TeamRemoved"
+	eventReasonSentryAPIError = "SentryAPIError"
+	eventReasonKeyNotFound    = "KeyNotFound"
+	eventReasonResourceExists = "ResourceExists"
+)
+
+// errKeyNotFound is returned when the client key recorded in the status of a
+// ClientKey no longer exists in Sentry.
+var errKeyNotFound = errors.New("key not found")
+
+// sentryError wraps err, an error returned by the Sentry API, and records it
+// as a Warning event on obj.
+func (r *reconcilerSet) sentryError(obj runtime.Object, err error, format string, args ...interface{}) error {
+	err = errors.Wrapf(err, format, args...)
+	r.recorder.Event(obj, corev1.EventTypeWarning, eventReasonSentryAPIError, err.Error())
+	return err
+}
@@ -0,0 +1,43 @@
+package sentrycontroller
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// InFlight counts the reconciles in progress, so that the Sentry API calls
+// they make can complete when the process is shutting down. The zero value is
+// ready to use.
+type InFlight struct {
+	n int64
+}
+
+// Wait blocks until no reconcile is in progress or ctx is done.
+func (f *InFlight) Wait(ctx context.Context) error {
+	t := time.NewTicker(50 * time.Millisecond)
+	defer t.Stop()
+	for atomic.LoadInt64(&f.n) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	return nil
+}
+
+// track returns a reconcile.Func counting its calls to fn in f. It returns fn
+// unchanged if f is nil.
+func (f *InFlight) track(fn reconcile.Func) reconcile.Func {
+	if f == nil {
+		return fn
+	}
+	return func(req reconcile.Request) (reconcile.Result, error) {
+		atomic.AddInt64(&f.n, 1)
+		defer atomic.AddInt64(&f.n, -1)
+		return fn(req)
+	}
+}
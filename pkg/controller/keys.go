@@ -0,0 +1,83 @@
+package sentrycontroller
+
+import (
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	"github.com/sr/kube-sentry-controller/pkg/sentry"
+)
+
+// clientKeyUpdate returns the update bringing key in line with spec, and the
+// names of the settings it changes besides the name. It returns a nil update
+// if key is up to date. Settings that are not set in spec are left unchanged.
+func clientKeyUpdate(spec sentryv1alpha1.ClientKeySpec, key *sentry.ClientKey) (*sentry.ClientKeyUpdate, []string) {
+	var (
+		update  sentry.ClientKeyUpdate
+		renamed bool
+		fields  []string
+	)
+
+	if spec.Name != key.Name {
+		update.Name = &spec.Name
+		renamed = true
+	}
+	if spec.IsActive != nil && *spec.IsActive != key.IsActive {
+		update.IsActive = spec.IsActive
+		fields = append(fields, "isActive")
+	}
+	if rl := spec.RateLimit; rl != nil {
+		want := sentry.ClientKeyRateLimit{Count: int(rl.Count), Window: int(rl.Window)}
+		if key.RateLimit == nil || *key.RateLimit != want {
+			update.RateLimit = &want
+			fields = append(fields, "rateLimit")
+		}
+	}
+	if sdk := spec.BrowserSDK; sdk != nil {
+		if sdk.Version != "" && sdk.Version != key.BrowserSDKVersion {
+			update.BrowserSDKVersion = &sdk.Version
+			fields = append(fields, "browserSdk.version")
+		}
+
+		var current sentry.ClientKeyLoaderOptions
+		if key.DynamicSDKLoaderOptions != nil {
+			current = *key.DynamicSDKLoaderOptions
+		}
+		want := current
+		if sdk.Replay != nil {
+			want.HasReplay = *sdk.Replay
+		}
+		if sdk.Performance != nil {
+			want.HasPerformance = *sdk.Performance
+		}
+		if sdk.Debug != nil {
+			want.HasDebug = *sdk.Debug
+		}
+		if want != current {
+			update.DynamicSDKLoaderOptions = &want
+			fields = append(fields, "browserSdk.loaderOptions")
+		}
+	}
+
+	if !renamed && len(fields) == 0 {
+		return nil, nil
+	}
+	return &update, fields
+}
+
+// setClientKeyStatus records the effective settings of key on status.
+func setClientKeyStatus(status *sentryv1alpha1.ClientKeyStatus, key *sentry.ClientKey) {
+	status.IsActive = key.IsActive
+
+	status.RateLimit = nil
+	if rl := key.RateLimit; rl != nil {
+		status.RateLimit = &sentryv1alpha1.ClientKeyRateLimit{Count: int32(rl.Count), Window: int32(rl.Window)}
+	}
+
+	status.BrowserSDK = nil
+	if key.BrowserSDKVersion != "" || key.DynamicSDKLoaderOptions != nil {
+		status.BrowserSDK = &sentryv1alpha1.ClientKeyBrowserSDK{Version: key.BrowserSDKVersion}
+		if opts := key.DynamicSDKLoaderOptions; opts != nil {
+			status.BrowserSDK.Replay = &opts.HasReplay
+			status.BrowserSDK.Performance = &opts.HasPerformance
+			status.BrowserSDK.Debug = &opts.HasDebug
+		}
+	}
+}
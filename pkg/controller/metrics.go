@@ -0,0 +1,92 @@
+package sentrycontroller
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var driftCorrections = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "sentry_controller_drift_corrections_total",
+		Help: "Number of times a Sentry object was changed outside of the controller and reverted, by kind.",
+	},
+	[]string{"kind"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(driftCorrections)
+}
+
+var objectsDesc = prometheus.NewDesc(
+	"sentry_controller_objects",
+	"Number of objects managed by the controller, by kind and readiness.",
+	[]string{"kind", "ready"},
+	nil,
+)
+
+// objectsCollector counts the Teams, Projects and ClientKeys in the cache of
+// the manager that match selector, if any, when metrics are collected.
+type objectsCollector struct {
+	kube     client.Client
+	selector labels.Selector
+}
+
+func (c *objectsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- objectsDesc
+}
+
+func (c *objectsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+	var opts []client.ListOption
+	if c.selector != nil {
+		opts = append(opts, client.MatchingLabelsSelector{Selector: c.selector})
+	}
+	statuses := make(map[string][]*sentryv1alpha1.ConditionedStatus)
+
+	teams := &sentryv1alpha1.TeamList{}
+	if err := c.kube.List(ctx, teams, opts...); err == nil {
+		for i := range teams.Items {
+			statuses["Team"] = append(statuses["Team"], &teams.Items[i].Status.ConditionedStatus)
+		}
+	}
+	projects := &sentryv1alpha1.ProjectList{}
+	if err := c.kube.List(ctx, projects, opts...); err == nil {
+		for i := range projects.Items {
+			statuses["Project"] = append(statuses["Project"], &projects.Items[i].Status.ConditionedStatus)
+		}
+	}
+	keys := &sentryv1alpha1.ClientKeyList{}
+	if err := c.kube.List(ctx, keys, opts...); err == nil {
+		for i := range keys.Items {
+			statuses["ClientKey"] = append(statuses["ClientKey"], &keys.Items[i].Status.ConditionedStatus)
+		}
+	}
+
+	for _, kind := range []string{"Team", "Project", "ClientKey"} {
+		count := map[bool]float64{true: 0, false: 0}
+		for _, s := range statuses[kind] {
+			count[s.IsReady()]++
+		}
+		for ready, n := range count {
+			ch <- prometheus.MustNewConstMetric(objectsDesc, prometheus.GaugeValue, n, kind, strconv.FormatBool(ready))
+		}
+	}
+}
+
+// registerObjectsCollector registers c to reg. Registering it again, e.g. when
+// Add is called more than once in a process, is not an error: the collector
+// registered first keeps reporting the objects.
+func registerObjectsCollector(reg prometheus.Registerer, c *objectsCollector) error {
+	if err := reg.Register(c); err != nil {
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			return err
+		}
+	}
+	return nil
+}
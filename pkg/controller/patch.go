@@ -0,0 +1,98 @@
+package sentrycontroller
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// kubeObject is a Kubernetes object reconciled by the controller.
+type kubeObject interface {
+	runtime.Object
+	metav1.Object
+}
+
+// addFinalizer adds the finalizer of the controller to obj.
+func (r *reconcilerSet) addFinalizer(ctx context.Context, obj kubeObject) error {
+	err := r.patchFinalizers(ctx, obj, func(o metav1.Object) {
+		if !hasFinalizer(o) {
+			o.SetFinalizers(append(o.GetFinalizers(), finalizerName))
+		}
+	})
+	return errors.Wrap(err, "failed to add finalizer")
+}
+
+// deleteFinalizer removes the finalizer of the controller from obj.
+func (r *reconcilerSet) deleteFinalizer(ctx context.Context, obj kubeObject) error {
+	err := r.patchFinalizers(ctx, obj, removeFinalizer)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrap(err, "failed to remove finalizer")
+}
+
+// patchFinalizers changes the finalizers of obj with update, and writes them
+// with a merge patch that leaves the rest of the object alone. The patch
+// carries the resourceVersion of obj, so that it is rejected if obj changed
+// since it was read and finalizers added or removed concurrently by others are
+// not lost. obj is then read again and update applied to its latest version.
+func (r *reconcilerSet) patchFinalizers(ctx context.Context, obj kubeObject, update func(metav1.Object)) error {
+	key := client.ObjectKey{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	retried := false
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if retried {
+			if err := r.kube.Get(ctx, key, obj); err != nil {
+				return err
+			}
+		}
+		retried = true
+
+		update(obj)
+		patch, err := finalizersPatch(obj)
+		if err != nil {
+			return err
+		}
+		return r.kube.Patch(ctx, obj, patch)
+	})
+}
+
+// patchStatus writes the status of obj with a merge patch from base, the
+// object as it was read before being reconciled. Unlike an update, the patch
+// does not fail when the object was edited in the meantime.
+func (r *reconcilerSet) patchStatus(ctx context.Context, obj, base runtime.Object) error {
+	return errors.Wrap(r.kube.Status().Patch(ctx, obj, client.MergeFrom(base)), "failed to update status")
+}
+
+// saveStatus writes the status of obj right away, with a merge patch from
+// base, so that the Sentry objects created so far are recorded even if the
+// rest of the reconcile fails. Unlike patchStatus, obj is left untouched.
+func (r *reconcilerSet) saveStatus(ctx context.Context, obj, base runtime.Object) error {
+	return r.patchStatus(ctx, obj.DeepCopyObject(), base)
+}
+
+// finalizersPatch returns a merge patch setting the finalizers of obj to its
+// current list, and its resourceVersion if it has one. The list is written
+// even when empty, rather than as null, as a merge patch replaces lists as a
+// whole.
+func finalizersPatch(obj metav1.Object) (client.Patch, error) {
+	finalizers := obj.GetFinalizers()
+	if finalizers == nil {
+		finalizers = []string{}
+	}
+	metadata := map[string]interface{}{"finalizers": finalizers}
+	if v := obj.GetResourceVersion(); v != "" {
+		metadata["resourceVersion"] = v
+	}
+	data, err := json.Marshal(map[string]interface{}{"metadata": metadata})
+	if err != nil {
+		return nil, err
+	}
+	return client.ConstantPatch(types.MergePatchType, data), nil
+}
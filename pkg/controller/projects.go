@@ -0,0 +1,90 @@
+package sentrycontroller
+
+import (
+	"sort"
+
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	"github.com/sr/kube-sentry-controller/pkg/sentry"
+)
+
+// projectUpdate returns the update bringing proj in line with spec, and the
+// names of the settings it changes besides the name and slug. It returns a nil
+// update if proj is up to date. Settings that are not set in spec are left
+// unchanged.
+func projectUpdate(spec sentryv1alpha1.ProjectSpec, proj *sentry.Project) (*sentry.ProjectUpdate, []string) {
+	var (
+		update  sentry.ProjectUpdate
+		changed bool
+		fields  []string
+	)
+
+	if spec.Slug != proj.Slug {
+		update.Slug = &spec.Slug
+		changed = true
+	}
+	if spec.Name != "" && spec.Name != proj.Name {
+		update.Name = &spec.Name
+		changed = true
+	}
+
+	if spec.Platform != "" && spec.Platform != proj.Platform {
+		update.Platform = &spec.Platform
+		fields = append(fields, "platform")
+	}
+	if spec.DefaultEnvironment != "" && spec.DefaultEnvironment != proj.DefaultEnvironment {
+		update.DefaultEnvironment = &spec.DefaultEnvironment
+		fields = append(fields, "defaultEnvironment")
+	}
+	if spec.ResolveAge != nil && int(*spec.ResolveAge) != proj.ResolveAge {
+		age := int(*spec.ResolveAge)
+		update.ResolveAge = &age
+		fields = append(fields, "resolveAge")
+	}
+	if spec.SubjectPrefix != "" && spec.SubjectPrefix != proj.SubjectPrefix {
+		update.SubjectPrefix = &spec.SubjectPrefix
+		fields = append(fields, "subjectPrefix")
+	}
+	if spec.DataScrubber != nil && *spec.DataScrubber != proj.DataScrubber {
+		update.DataScrubber = spec.DataScrubber
+		fields = append(fields, "dataScrubber")
+	}
+	if spec.SensitiveFields != nil && !sameStrings(spec.SensitiveFields, proj.SensitiveFields) {
+		update.SensitiveFields = &spec.SensitiveFields
+		fields = append(fields, "sensitiveFields")
+	}
+	if spec.SafeFields != nil && !sameStrings(spec.SafeFields, proj.SafeFields) {
+		update.SafeFields = &spec.SafeFields
+		fields = append(fields, "safeFields")
+	}
+	if spec.ScrubIPAddresses != nil && *spec.ScrubIPAddresses != proj.ScrubIPAddresses {
+		update.ScrubIPAddresses = spec.ScrubIPAddresses
+		fields = append(fields, "scrubIPAddresses")
+	}
+	if spec.AllowedDomains != nil && !sameStrings(spec.AllowedDomains, proj.AllowedDomains) {
+		update.AllowedDomains = &spec.AllowedDomains
+		fields = append(fields, "allowedDomains")
+	}
+
+	if !changed && len(fields) == 0 {
+		return nil, nil
+	}
+	return &update, fields
+}
+
+// sameStrings reports whether a and b hold the same strings, regardless of
+// their order.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
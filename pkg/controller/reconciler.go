@@ -2,8 +2,7 @@ package sentrycontroller
 
 import (
 	"context"
-	"net/http"
-	"reflect"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -12,9 +11,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -22,13 +22,122 @@ const finalizerName = "sentry.sr.github.com"
 
 // reconcilerSet is a set of reconcile.Reconciler that reconcile Sentry API objects.
 type reconcilerSet struct {
-	scheme  *runtime.Scheme
-	kube    client.Client // kubernetes API client
-	sentry  sentry.Client // sentry API client
-	timeout time.Duration // timeout for reconcilation attempts
+	scheme   *runtime.Scheme
+	kube     client.Client        // kubernetes API client
+	sentry   sentry.Client        // sentry API client for organizations without an Organization object
+	recorder record.EventRecorder // records events about Sentry API mutations
+	timeout  time.Duration        // timeout for reconcilation attempts
+
+	// deletionPolicy applies to objects that do not set one. Empty means
+	// Delete.
+	deletionPolicy sentryv1alpha1.DeletionPolicy
+	// resyncPeriod applies to objects that do not override it.
+	resyncPeriod time.Duration
+	// selector restricts the Teams, Projects and ClientKeys reconciled. Nil
+	// means all.
+	selector labels.Selector
+
+	// newSentry returns a sentry API client authenticated with the given
+	// token. An empty endpoint selects the default Sentry API endpoint.
+	newSentry func(token, endpoint string) (sentry.Client, error)
+	// clients caches the clients returned by newSentry for Organizations.
+	clients clientCache
+}
+
+// +kubebuilder:rbac:groups=sentry.sr.github.com,resources=organizations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=sentry.sr.github.com,resources=organizations/status,verbs=get;update;patch
+func (r *reconcilerSet) Organization(request reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	instance := &sentryv1alpha1.Organization{}
+	if err := r.kube.Get(ctx, request.NamespacedName, instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.clients.forget(request.Name)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	base := instance.DeepCopy()
+	err := r.syncOrganization(ctx, instance)
+	setSyncConditions(&instance.Status.ConditionedStatus, instance.Generation, false, err)
+
+	if serr := r.patchStatus(ctx, instance, base); serr != nil && err == nil {
+		err = serr
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: r.resyncAfter(instance)}, nil
+}
+
+func (r *reconcilerSet) syncOrganization(ctx context.Context, instance *sentryv1alpha1.Organization) error {
+	cli, err := r.organizationClient(ctx, instance)
+	if err != nil {
+		return err
+	}
+
+	org, _, err := cli.GetOrganization(ctx, instance.Spec.Slug)
+	if err != nil {
+		return r.sentryError(instance, err, "failed to get organization %s", instance.Spec.Slug)
+	}
+	instance.Status.Slug = org.Slug
+	instance.Status.Name = org.Name
+	return nil
+}
+
+// sentryFor returns the sentry API client for the organization with the given
+// slug. Organizations that are described by an Organization object are managed
+// with the credentials from its Secret, others with the controller-wide client.
+func (r *reconcilerSet) sentryFor(ctx context.Context, org string) (sentry.Client, error) {
+	if cli := r.clients.lookup(org); cli != nil {
+		return cli, nil
+	}
+
+	orgs := &sentryv1alpha1.OrganizationList{}
+	if err := r.kube.List(ctx, orgs); err != nil {
+		return nil, errors.Wrap(err, "failed to list organizations")
+	}
+	for i := range orgs.Items {
+		if orgs.Items[i].Spec.Slug == org {
+			return r.organizationClient(ctx, &orgs.Items[i])
+		}
+	}
+	if r.sentry == nil {
+		return nil, errors.Errorf("no Organization found for sentry organization %s", org)
+	}
+	return r.sentry, nil
+}
+
+// organizationClient returns the sentry API client of instance, authenticated
+// with the credentials from its Secret. The client is cached until the
+// Organization is deleted or the credentials change, which reconciles it.
+func (r *reconcilerSet) organizationClient(ctx context.Context, instance *sentryv1alpha1.Organization) (sentry.Client, error) {
+	ref := instance.Spec.SecretRef
+	tokenKey, endpointKey := ref.TokenKey, ref.EndpointKey
+	if tokenKey == "" {
+		tokenKey = "token"
+	}
+	if endpointKey == "" {
+		endpointKey = "endpoint"
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.kube.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		r.clients.forget(instance.Name)
+		return nil, errors.Wrapf(err, "failed to get secret %s/%s of organization %s", ref.Namespace, ref.Name, instance.Name)
+	}
+	token := secret.Data[tokenKey]
+	if len(token) == 0 {
+		r.clients.forget(instance.Name)
+		return nil, errors.Errorf("secret %s/%s of organization %s has no %s key", ref.Namespace, ref.Name, instance.Name, tokenKey)
+	}
+	return r.clients.get(instance.Name, instance.Spec.Slug, string(token), string(secret.Data[endpointKey]), r.newSentry)
 }
 
 // +kubebuilder:rbac:groups=sentry.sr.github.com,resources=teams,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sentry.sr.github.com,resources=teams/status,verbs=get;update;patch
 func (r *reconcilerSet) Team(request reconcile.Request) (reconcile.Result, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
 	defer cancel()
@@ -41,62 +150,137 @@ func (r *reconcilerSet) Team(request reconcile.Request) (reconcile.Result, error
 		return reconcile.Result{}, err
 	}
 
+	if !r.selects(instance) {
+		return reconcile.Result{}, nil
+	}
+
 	if !instance.ObjectMeta.DeletionTimestamp.IsZero() {
 		if !hasFinalizer(instance) {
 			return reconcile.Result{}, nil
 		}
 
-		if instance.Status.Slug != "" {
-			resp, err := r.sentry.DeleteTeam(ctx, instance.Status.OrganizationSlug, instance.Status.Slug)
+		if instance.Status.Slug != "" && r.retain(instance.Spec.DeletionPolicy) {
+			r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonOrphaned, "Retained Sentry team %s in organization %s", instance.Status.Slug, instance.Status.OrganizationSlug)
+		} else if instance.Status.Slug != "" {
+			cli, err := r.sentryFor(ctx, instance.Status.OrganizationSlug)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			_, err = cli.DeleteTeam(ctx, instance.Status.OrganizationSlug, instance.Status.Slug)
 
-			if err != nil && resp.StatusCode != http.StatusNotFound {
-				return reconcile.Result{}, errors.Wrapf(err, "failed to delete team %s", instance.Status.Slug)
+			if err != nil && !sentry.IsNotFound(err) {
+				return reconcile.Result{}, r.sentryError(instance, err, "failed to delete team %s", instance.Status.Slug)
+			}
+			if err == nil {
+				r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonDeleted, "Deleted Sentry team %s", instance.Status.Slug)
 			}
 		}
 
+		base := instance.DeepCopy()
 		instance.Status = sentryv1alpha1.TeamStatus{}
-		removeFinalizer(instance)
-
-		return reconcile.Result{}, r.kube.Update(ctx, instance)
+		if err := r.patchStatus(ctx, instance, base); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, r.deleteFinalizer(ctx, instance)
 	}
 
 	if !hasFinalizer(instance) {
-		instance.ObjectMeta.Finalizers = append(instance.ObjectMeta.Finalizers, finalizerName)
+		if err := r.addFinalizer(ctx, instance); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	base := instance.DeepCopy()
+	d := newDrift(&instance.Status.ConditionedStatus, instance.Generation)
+	err := r.syncTeam(ctx, instance, d)
+	setSyncConditions(&instance.Status.ConditionedStatus, instance.Generation, instance.Status.Slug != "", err)
+	if err == nil {
+		r.recordDrift(instance, &instance.Status.ConditionedStatus, "Team", d)
+	}
+
+	if serr := r.patchStatus(ctx, instance, base); serr != nil && err == nil {
+		err = serr
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: r.resyncAfter(instance)}, nil
+}
 
-		if err := r.kube.Update(ctx, instance); err != nil {
-			return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+func (r *reconcilerSet) syncTeam(ctx context.Context, instance *sentryv1alpha1.Team, d *drift) error {
+	org := instance.Spec.OrganizationSlug
+	if instance.Status.Slug != "" {
+		org = instance.Status.OrganizationSlug
+	}
+	cli, err := r.sentryFor(ctx, org)
+	if err != nil {
+		return err
+	}
+
+	if instance.Status.Slug == "" && instance.Spec.Adopt {
+		if err := r.adoptTeam(ctx, cli, instance); err != nil {
+			return err
 		}
 	}
 
 	if instance.Status.Slug == "" {
-		team, _, err := r.sentry.CreateTeam(ctx, instance.Spec.OrganizationSlug, instance.Spec.Slug, instance.Spec.Slug)
+		name := instance.Spec.Name
+		if name == "" {
+			name = instance.Spec.Slug
+		}
+		team, _, err := cli.CreateTeam(ctx, instance.Spec.OrganizationSlug, name, instance.Spec.Slug)
+		if sentry.IsConflict(err) {
+			return &adoptionRefusedError{kind: "team", name: instance.Spec.Slug, reason: "set spec.adopt to manage the existing team"}
+		}
 		if err != nil {
-			return reconcile.Result{}, errors.Wrapf(err, "failed to create team %s", instance.Spec.Slug)
+			return r.sentryError(instance, err, "failed to create team %s", instance.Spec.Slug)
 		}
 		instance.Status.Slug = team.Slug
+		instance.Status.Name = team.Name
 		instance.Status.OrganizationSlug = instance.Spec.OrganizationSlug
-
-		return reconcile.Result{}, r.kube.Update(ctx, instance)
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonCreated, "Created Sentry team %s in organization %s", team.Slug, instance.Status.OrganizationSlug)
+		return nil
 	}
 
-	team, _, err := r.sentry.GetTeam(ctx, instance.Status.OrganizationSlug, instance.Status.Slug)
+	team, _, err := cli.GetTeam(ctx, instance.Status.OrganizationSlug, instance.Status.Slug)
 	if err != nil {
-		return reconcile.Result{}, errors.Wrapf(err, "failed to get team %s", instance.Status.Slug)
+		return r.sentryError(instance, err, "failed to get team %s", instance.Status.Slug)
 	}
 
-	if team.Slug == instance.Spec.Slug {
-		return reconcile.Result{}, nil
+	// Only update the fields that changed. The name is left alone unless it
+	// is set explicitly.
+	var newName, newSlug string
+	if instance.Spec.Name != "" && team.Name != instance.Spec.Name {
+		newName = instance.Spec.Name
+	}
+	if team.Slug != instance.Spec.Slug {
+		newSlug = instance.Spec.Slug
+	}
+	if newName == "" && newSlug == "" {
+		instance.Status.Name = team.Name
+		return nil
 	}
 
-	team, _, err = r.sentry.UpdateTeam(ctx, instance.Status.OrganizationSlug, instance.Status.Slug, instance.Spec.Slug, instance.Spec.Slug)
+	oldName := team.Name
+	updated, _, err := cli.UpdateTeam(ctx, instance.Status.OrganizationSlug, instance.Status.Slug, newName, newSlug)
 	if err != nil {
-		return reconcile.Result{}, errors.Wrapf(err, "failed to update team %s", team.Slug)
+		return r.sentryError(instance, err, "failed to update team %s", instance.Status.Slug)
+	}
+	if newSlug != "" {
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonRenamed, "Renamed Sentry team %s to %s", instance.Status.Slug, updated.Slug)
+		d.add("slug")
 	}
-	instance.Status.Slug = team.Slug
-	return reconcile.Result{}, r.kube.Update(ctx, instance)
+	if newName != "" {
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonRenamed, "Renamed Sentry team %s from %q to %q", updated.Slug, oldName, updated.Name)
+		d.add("name")
+	}
+	instance.Status.Slug = updated.Slug
+	instance.Status.Name = updated.Name
+	return nil
 }
 
-// +kubebuilder:rbac:groups=sentry.sr.github.com,resources=sentryprojects,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sentry.sr.github.com,resources=projects,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sentry.sr.github.com,resources=projects/status,verbs=get;update;patch
 func (r *reconcilerSet) Project(request reconcile.Request) (reconcile.Result, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
 	defer cancel()
@@ -110,63 +294,188 @@ func (r *reconcilerSet) Project(request reconcile.Request) (reconcile.Result, er
 		return reconcile.Result{}, err
 	}
 
+	if !r.selects(instance) {
+		return reconcile.Result{}, nil
+	}
+
 	if !instance.ObjectMeta.DeletionTimestamp.IsZero() {
 		if !hasFinalizer(instance) {
 			return reconcile.Result{}, err
 		}
 
-		if instance.Status.Slug != "" {
-			resp, err := r.sentry.DeleteProject(ctx, instance.Status.OrganizationSlug, instance.Status.Slug)
+		if instance.Status.Slug != "" && r.retain(instance.Spec.DeletionPolicy) {
+			r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonOrphaned, "Retained Sentry project %s in organization %s", instance.Status.Slug, instance.Status.OrganizationSlug)
+		} else if instance.Status.Slug != "" {
+			cli, err := r.sentryFor(ctx, instance.Status.OrganizationSlug)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			_, err = cli.DeleteProject(ctx, instance.Status.OrganizationSlug, instance.Status.Slug)
 
-			if err != nil && resp.StatusCode != http.StatusNotFound {
-				return reconcile.Result{}, errors.Wrapf(err, "failed to delete project %s", instance.Status.Slug)
+			if err != nil && !sentry.IsNotFound(err) {
+				return reconcile.Result{}, r.sentryError(instance, err, "failed to delete project %s", instance.Status.Slug)
+			}
+			if err == nil {
+				r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonDeleted, "Deleted Sentry project %s", instance.Status.Slug)
 			}
 		}
 
-		removeFinalizer(instance)
+		base := instance.DeepCopy()
 		instance.Status = sentryv1alpha1.ProjectStatus{}
-
-		return reconcile.Result{}, r.kube.Update(ctx, instance)
+		if err := r.patchStatus(ctx, instance, base); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, r.deleteFinalizer(ctx, instance)
 	}
 
 	if !hasFinalizer(instance) {
-		instance.ObjectMeta.Finalizers = append(instance.ObjectMeta.Finalizers, finalizerName)
+		if err := r.addFinalizer(ctx, instance); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
 
-		if err := r.kube.Update(ctx, instance); err != nil {
-			return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	base := instance.DeepCopy()
+	d := newDrift(&instance.Status.ConditionedStatus, instance.Generation)
+	err = r.syncProject(ctx, instance, d)
+	setSyncConditions(&instance.Status.ConditionedStatus, instance.Generation, instance.Status.Slug != "", err)
+	if err == nil {
+		r.recordDrift(instance, &instance.Status.ConditionedStatus, "Project", d)
+	}
+	if isParentNotReady(err) {
+		// Requeued by the watch on the Team once it changes.
+		err = nil
+	}
+
+	if serr := r.patchStatus(ctx, instance, base); serr != nil && err == nil {
+		err = serr
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: r.resyncAfter(instance)}, nil
+}
+
+func (r *reconcilerSet) syncProject(ctx context.Context, instance *sentryv1alpha1.Project, d *drift) error {
+	org, teams, err := r.projectTeams(ctx, instance)
+	if err != nil {
+		return err
+	}
+	if instance.Status.Slug != "" {
+		org = instance.Status.OrganizationSlug
+	}
+	cli, err := r.sentryFor(ctx, org)
+	if err != nil {
+		return err
+	}
+
+	if instance.Status.Slug == "" && instance.Spec.Adopt {
+		if err := r.adoptProject(ctx, cli, instance, org); err != nil {
+			return err
 		}
 	}
 
+	var (
+		proj    *sentry.Project
+		current []string
+	)
 	if instance.Status.Slug == "" {
-		proj, _, err := r.sentry.CreateProject(ctx, instance.Spec.OrganizationSlug, instance.Spec.TeamSlug, instance.Spec.Slug, instance.Spec.Slug)
+		name := instance.Spec.Name
+		if name == "" {
+			name = instance.Spec.Slug
+		}
+		proj, _, err = cli.CreateProject(ctx, org, teams[0], name, instance.Spec.Slug)
+		if sentry.IsConflict(err) {
+			return &adoptionRefusedError{kind: "project", name: instance.Spec.Slug, reason: "set spec.adopt to manage the existing project"}
+		}
 		if err != nil {
-			return reconcile.Result{}, errors.Wrapf(err, "failed to create project %s", instance.Spec.Slug)
+			return r.sentryError(instance, err, "failed to create project %s", instance.Spec.Slug)
 		}
 		instance.Status.Slug = proj.Slug
-		instance.Status.TeamSlug = instance.Spec.TeamSlug
-		instance.Status.OrganizationSlug = instance.Spec.OrganizationSlug
-		return reconcile.Result{}, r.kube.Update(ctx, instance)
+		instance.Status.TeamSlug = teams[0]
+		instance.Status.Teams = teams[:1]
+		instance.Status.OrganizationSlug = org
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonCreated, "Created Sentry project %s in organization %s", proj.Slug, instance.Status.OrganizationSlug)
+		current = teams[:1]
+	} else {
+		proj, _, err = cli.GetProject(ctx, instance.Status.OrganizationSlug, instance.Status.Slug)
+		if err != nil {
+			return r.sentryError(instance, err, "failed to get project %s", instance.Status.Slug)
+		}
+		for _, t := range proj.Teams {
+			current = append(current, t.Slug)
+		}
 	}
 
-	proj, _, err := r.sentry.GetProject(ctx, instance.Status.OrganizationSlug, instance.Status.Slug)
-	if err != nil {
-		return reconcile.Result{}, errors.Wrapf(err, "failed to get project %s", instance.Status.Slug)
+	// Only update the fields that changed. The name and settings are left
+	// alone unless they are set explicitly.
+	name := proj.Name
+	if update, fields := projectUpdate(instance.Spec, proj); update != nil {
+		updated, _, err := cli.UpdateProject(ctx, instance.Status.OrganizationSlug, instance.Status.Slug, update)
+		if err != nil {
+			return r.sentryError(instance, err, "failed to update project %s", instance.Status.Slug)
+		}
+		if update.Slug != nil {
+			r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonRenamed, "Renamed Sentry project %s to %s", instance.Status.Slug, updated.Slug)
+			d.add("slug")
+		}
+		if update.Name != nil {
+			r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonRenamed, "Renamed Sentry project %s from %q to %q", updated.Slug, name, updated.Name)
+			d.add("name")
+		}
+		if len(fields) > 0 {
+			r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonUpdated, "Updated %s of Sentry project %s", strings.Join(fields, ", "), updated.Slug)
+			d.add(fields...)
+		}
+		instance.Status.Slug = updated.Slug
+		name = updated.Name
 	}
+	instance.Status.Name = name
 
-	if proj.Slug == instance.Spec.Slug {
-		return reconcile.Result{}, nil
+	return r.syncProjectTeams(ctx, cli, instance, current, teams, d)
+}
+
+// syncProjectTeams adds the project to the teams it does not belong to yet,
+// then removes it from the teams that are no longer listed in its spec.
+func (r *reconcilerSet) syncProjectTeams(ctx context.Context, cli sentry.Client, instance *sentryv1alpha1.Project, current, teams []string, d *drift) error {
+	org, slug := instance.Status.OrganizationSlug, instance.Status.Slug
+
+	if !sameStrings(current, teams) {
+		d.add("teams")
 	}
 
-	proj, _, err = r.sentry.UpdateProject(ctx, instance.Status.OrganizationSlug, proj.Slug, instance.Spec.Slug, instance.Spec.Slug)
-	if err != nil {
-		return reconcile.Result{}, errors.Wrapf(err, "failed to update project %s", instance.Status.Slug)
+	for _, team := range teams {
+		if containsString(current, team) {
+			continue
+		}
+		if _, _, err := cli.AddProjectTeam(ctx, org, slug, team); err != nil {
+			return r.sentryError(instance, err, "failed to add team %s to project %s", team, slug)
+		}
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonTeamAdded, "Added Sentry team %s to project %s", team, slug)
 	}
-	instance.Status.Slug = proj.Slug
-	return reconcile.Result{}, r.kube.Update(ctx, instance)
+	for _, team := range current {
+		if containsString(teams, team) {
+			continue
+		}
+		_, err := cli.RemoveProjectTeam(ctx, org, slug, team)
+		if err != nil && !sentry.IsNotFound(err) {
+			return r.sentryError(instance, err, "failed to remove team %s from project %s", team, slug)
+		}
+		if err == nil {
+			r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonTeamRemoved, "Removed Sentry team %s from project %s", team, slug)
+		}
+	}
+
+	instance.Status.TeamSlug = teams[0]
+	instance.Status.Teams = teams
+	return nil
 }
 
-// +kubebuilder:rbac:groups=sentry.sr.github.com,resources=teams,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sentry.sr.github.com,resources=clientkeys,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sentry.sr.github.com,resources=projects,verbs=get;list;watch
+// +kubebuilder:rbac:groups=sentry.sr.github.com,resources=clientkeys/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 func (r *reconcilerSet) ClientKey(request reconcile.Request) (reconcile.Result, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
 	defer cancel()
@@ -180,51 +489,122 @@ func (r *reconcilerSet) ClientKey(request reconcile.Request) (reconcile.Result,
 		return reconcile.Result{}, err
 	}
 
+	if !r.selects(instance) {
+		return reconcile.Result{}, nil
+	}
+
 	if !instance.ObjectMeta.DeletionTimestamp.IsZero() {
 		if !hasFinalizer(instance) {
 			return reconcile.Result{}, nil
 		}
 
-		if instance.Status.ID != "" {
-			resp, err := r.sentry.DeleteClientKey(ctx, instance.Status.OrganizationSlug, instance.Status.ProjectSlug, instance.Status.ID)
+		if instance.Status.ID != "" && r.retain(instance.Spec.DeletionPolicy) {
+			r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonOrphaned, "Retained Sentry client key %s of project %s", instance.Status.ID, instance.Status.ProjectSlug)
+		} else if instance.Status.ID != "" {
+			cli, err := r.sentryFor(ctx, instance.Status.OrganizationSlug)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			_, err = cli.DeleteClientKey(ctx, instance.Status.OrganizationSlug, instance.Status.ProjectSlug, instance.Status.ID)
 
-			if err != nil && resp.StatusCode != http.StatusNotFound {
-				return reconcile.Result{}, errors.Wrapf(err, "failed to delete client key for project %s", instance.Spec.ProjectSlug)
+			if err != nil && !sentry.IsNotFound(err) {
+				return reconcile.Result{}, r.sentryError(instance, err, "failed to delete client key for project %s", instance.Status.ProjectSlug)
+			}
+			if err == nil {
+				r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonDeleted, "Deleted Sentry client key %s", instance.Status.ID)
+			}
+
+			for _, prev := range instance.Status.PreviousKeys {
+				if prev.Deleted {
+					continue
+				}
+				_, err := cli.DeleteClientKey(ctx, instance.Status.OrganizationSlug, instance.Status.ProjectSlug, prev.ID)
+				if err != nil && !sentry.IsNotFound(err) {
+					return reconcile.Result{}, r.sentryError(instance, err, "failed to delete previous client key %s", prev.ID)
+				}
 			}
 		}
 
-		removeFinalizer(instance)
+		base := instance.DeepCopy()
 		instance.Status = sentryv1alpha1.ClientKeyStatus{}
-
-		return reconcile.Result{}, r.kube.Update(ctx, instance)
+		if err := r.patchStatus(ctx, instance, base); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, r.deleteFinalizer(ctx, instance)
 	}
 
 	if !hasFinalizer(instance) {
-		instance.ObjectMeta.Finalizers = append(instance.ObjectMeta.Finalizers, finalizerName)
+		if err := r.addFinalizer(ctx, instance); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	now := time.Now()
+	base := instance.DeepCopy()
+	d := newDrift(&instance.Status.ConditionedStatus, instance.Generation)
+	err = r.syncClientKey(ctx, instance, now, d)
+	setSyncConditions(&instance.Status.ConditionedStatus, instance.Generation, instance.Status.ID != "", err)
+	if err == nil {
+		r.recordDrift(instance, &instance.Status.ConditionedStatus, "ClientKey", d)
+	}
+	if isParentNotReady(err) {
+		// Requeued by the watch on the Project once it changes.
+		err = nil
+	}
+
+	if serr := r.patchStatus(ctx, instance, base); serr != nil && err == nil {
+		err = serr
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: minRequeue(clientKeyRequeueAfter(instance, now), r.resyncAfter(instance))}, nil
+}
 
-		if err := r.kube.Update(ctx, instance); err != nil {
-			return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+func (r *reconcilerSet) syncClientKey(ctx context.Context, instance *sentryv1alpha1.ClientKey, now time.Time, d *drift) error {
+	org, project, err := r.clientKeyProject(ctx, instance)
+	if err != nil {
+		return err
+	}
+	if instance.Status.ID != "" {
+		org = instance.Status.OrganizationSlug
+		if instance.Spec.ProjectRef != nil {
+			// Follow the referenced Project when its slug changes.
+			instance.Status.ProjectSlug = project
+		}
+	}
+	cli, err := r.sentryFor(ctx, org)
+	if err != nil {
+		return err
+	}
+
+	if instance.Status.ID == "" && instance.Spec.KeyID != "" && !instance.Spec.Adopt {
+		return &adoptionRefusedError{kind: "client key", name: instance.Spec.KeyID, reason: "set spec.adopt to manage the existing key"}
+	}
+	if instance.Status.ID == "" && instance.Spec.Adopt {
+		if err := r.adoptClientKey(ctx, cli, instance, org, project); err != nil {
+			return err
 		}
 	}
 
 	var key *sentry.ClientKey
 	if instance.Status.ID == "" {
-		key, _, err = r.sentry.CreateClientKey(ctx, instance.Spec.OrganizationSlug, instance.Spec.ProjectSlug, instance.Spec.Name)
+		k, _, err := cli.CreateClientKey(ctx, org, project, instance.Spec.Name)
 		if err != nil {
-			return reconcile.Result{}, errors.Wrapf(err, "failed to create client key for project %s", instance.Spec.ProjectSlug)
+			return r.sentryError(instance, err, "failed to create client key for project %s", project)
 		}
+		key = k
 
 		instance.Status.ID = key.ID
-		instance.Status.ProjectSlug = instance.Spec.ProjectSlug
-		instance.Status.OrganizationSlug = instance.Spec.OrganizationSlug
-
-		if err := r.kube.Update(ctx, instance); err != nil {
-			return reconcile.Result{}, err
-		}
+		instance.Status.ProjectSlug = project
+		instance.Status.OrganizationSlug = org
+		instance.Status.KeyCreationTime = &metav1.Time{Time: now}
+		instance.Status.RotationRequest = instance.Annotations[rotateAnnotation]
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonCreated, "Created Sentry client key %s for project %s", key.ID, instance.Status.ProjectSlug)
 	} else {
-		keys, _, err := r.sentry.GetClientKeys(ctx, instance.Status.OrganizationSlug, instance.Status.ProjectSlug)
+		keys, _, err := cli.GetClientKeys(ctx, instance.Status.OrganizationSlug, instance.Status.ProjectSlug)
 		if err != nil {
-			return reconcile.Result{}, err
+			return r.sentryError(instance, err, "failed to get client keys for project %s", instance.Status.ProjectSlug)
 		}
 		for _, k := range keys {
 			if k.ID == instance.Status.ID {
@@ -233,50 +613,69 @@ func (r *reconcilerSet) ClientKey(request reconcile.Request) (reconcile.Result,
 			}
 		}
 		if key == nil {
-			return reconcile.Result{}, errors.New("key not found")
+			r.recorder.Eventf(instance, corev1.EventTypeWarning, eventReasonKeyNotFound, "Sentry client key %s not found in project %s", instance.Status.ID, instance.Status.ProjectSlug)
+			return errKeyNotFound
 		}
 	}
+	if instance.Status.KeyCreationTime == nil {
+		// Keys created before rotation was supported are rotated one period
+		// after they are first seen.
+		instance.Status.KeyCreationTime = &metav1.Time{Time: now}
+	}
 
-	if key.Name != instance.Spec.Name {
-		if _, err := r.sentry.UpdateClientKey(ctx, instance.Status.OrganizationSlug, instance.Status.ProjectSlug, instance.Status.ID, instance.Spec.Name); err != nil {
-			return reconcile.Result{}, errors.Wrap(err, "failed to rename client key")
+	if rotationDue(instance, now) {
+		if key, err = r.rotateClientKey(ctx, cli, instance, now); err != nil {
+			return err
 		}
 	}
 
-	secret := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: instance.Namespace,
-			Name:      instance.Name,
-		},
-		Type: corev1.SecretType("Opaque"),
-		Data: map[string][]byte{
-			"dsn.secret": []byte(key.DSN.Secret),
-			"dsn.csp":    []byte(key.DSN.CSP),
-			"dsn.public": []byte(key.DSN.Public),
-		},
+	// Only update the fields that changed. Settings are left alone unless
+	// they are set explicitly.
+	if update, fields := clientKeyUpdate(instance.Spec, key); update != nil {
+		updated, _, err := cli.UpdateClientKey(ctx, instance.Status.OrganizationSlug, instance.Status.ProjectSlug, instance.Status.ID, update)
+		if err != nil {
+			return r.sentryError(instance, err, "failed to update client key %s", instance.Status.ID)
+		}
+		if update.Name != nil {
+			r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonRenamed, "Renamed Sentry client key %s to %q", instance.Status.ID, instance.Spec.Name)
+			d.add("name")
+		}
+		if len(fields) > 0 {
+			r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonUpdated, "Updated %s of Sentry client key %s", strings.Join(fields, ", "), instance.Status.ID)
+			d.add(fields...)
+		}
+		if updated.DSN == nil {
+			updated.DSN = key.DSN
+		}
+		key = updated
 	}
+	setClientKeyStatus(&instance.Status, key)
 
-	if err := controllerutil.SetControllerReference(instance, secret, r.scheme); err != nil {
-		return reconcile.Result{}, errors.Wrap(err, "failed to set controller reference on secret")
+	if err := r.syncSecret(ctx, instance, key); err != nil {
+		return err
 	}
-
-	found := &corev1.Secret{}
-	err = r.kube.Get(ctx, client.ObjectKey{Namespace: secret.Namespace, Name: secret.Name}, found)
-	if err != nil {
-		if !apierrors.IsNotFound(err) {
-			return reconcile.Result{}, err
-		}
-
-		err := r.kube.Create(ctx, secret)
-		return reconcile.Result{}, errors.Wrapf(err, "failed to create secret")
+	if err := r.syncConfigMap(ctx, instance, key); err != nil {
+		return err
 	}
+	return r.expirePreviousKeys(ctx, cli, instance, now)
+}
 
-	if reflect.DeepEqual(secret.Data, found.Data) {
-		return reconcile.Result{}, nil
+// retain reports whether the Sentry object of a Kubernetes object with the
+// given deletion policy must be left in Sentry when the object is deleted.
+func (r *reconcilerSet) retain(policy sentryv1alpha1.DeletionPolicy) bool {
+	if policy == "" {
+		policy = r.deletionPolicy
 	}
+	return policy == sentryv1alpha1.DeletionPolicyRetain
+}
 
-	found.Data = secret.Data
-	return reconcile.Result{}, r.kube.Update(ctx, found)
+func containsString(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+	return false
 }
 
 func hasFinalizer(obj metav1.Object) bool {
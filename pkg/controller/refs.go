@@ -0,0 +1,114 @@
+package sentrycontroller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const reasonParentNotReady = "ParentNotReady"
+
+// parentNotReadyError is returned when the Team or Project referenced by an
+// object does not exist or is not Ready yet. The reconcilers watch referenced
+// objects, so the dependent object is requeued once its parent changes.
+type parentNotReadyError struct {
+	kind string
+	name string
+}
+
+func (e *parentNotReadyError) Error() string {
+	return fmt.Sprintf("%s %s is not ready", e.kind, e.name)
+}
+
+func isParentNotReady(err error) bool {
+	_, ok := errors.Cause(err).(*parentNotReadyError)
+	return ok
+}
+
+// projectTeams returns the organization slug of the given project and the
+// slugs of the teams it belongs to, owning team first. The slugs of referenced
+// Teams are resolved from their status.
+func (r *reconcilerSet) projectTeams(ctx context.Context, instance *sentryv1alpha1.Project) (org string, teams []string, err error) {
+	org = instance.Spec.OrganizationSlug
+
+	refs := instance.Spec.TeamRefs
+	if ref := instance.Spec.TeamRef; ref != nil {
+		refs = append([]corev1.LocalObjectReference{*ref}, refs...)
+	} else if instance.Spec.TeamSlug != "" {
+		teams = append(teams, instance.Spec.TeamSlug)
+	}
+
+	for _, ref := range refs {
+		team, err := r.readyTeam(ctx, instance.Namespace, ref.Name)
+		if err != nil {
+			return "", nil, err
+		}
+		if org == "" {
+			org = team.Status.OrganizationSlug
+		}
+		teams = appendUnique(teams, team.Status.Slug)
+	}
+	for _, slug := range instance.Spec.Teams {
+		teams = appendUnique(teams, slug)
+	}
+
+	if len(teams) == 0 {
+		return "", nil, errors.Errorf("project %s has no team", instance.Name)
+	}
+	return org, teams, nil
+}
+
+func (r *reconcilerSet) readyTeam(ctx context.Context, namespace, name string) (*sentryv1alpha1.Team, error) {
+	team := &sentryv1alpha1.Team{}
+	if err := r.kube.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, team); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, &parentNotReadyError{kind: "Team", name: name}
+		}
+		return nil, errors.Wrapf(err, "failed to get team %s", name)
+	}
+	if !team.Status.IsReady() || team.Status.Slug == "" {
+		return nil, &parentNotReadyError{kind: "Team", name: name}
+	}
+	return team, nil
+}
+
+// clientKeyProject returns the organization and project slugs of the given
+// client key, resolved from the status of the referenced Project if the key
+// has a ProjectRef.
+func (r *reconcilerSet) clientKeyProject(ctx context.Context, instance *sentryv1alpha1.ClientKey) (org, project string, err error) {
+	ref := instance.Spec.ProjectRef
+	if ref == nil {
+		return instance.Spec.OrganizationSlug, instance.Spec.ProjectSlug, nil
+	}
+
+	parent := &sentryv1alpha1.Project{}
+	if err := r.kube.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: ref.Name}, parent); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", "", &parentNotReadyError{kind: "Project", name: ref.Name}
+		}
+		return "", "", errors.Wrapf(err, "failed to get project %s", ref.Name)
+	}
+	if !parent.Status.IsReady() || parent.Status.Slug == "" {
+		return "", "", &parentNotReadyError{kind: "Project", name: ref.Name}
+	}
+
+	org = instance.Spec.OrganizationSlug
+	if org == "" {
+		org = parent.Status.OrganizationSlug
+	}
+	return org, parent.Status.Slug, nil
+}
+
+func appendUnique(slugs []string, slug string) []string {
+	for _, s := range slugs {
+		if s == slug {
+			return slugs
+		}
+	}
+	return append(slugs, slug)
+}
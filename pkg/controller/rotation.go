@@ -0,0 +1,139 @@
+package sentrycontroller
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	"github.com/sr/kube-sentry-controller/pkg/sentry"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// rotateAnnotation requests the rotation of a ClientKey when set to a
+	// value the key has not been rotated for yet, e.g. a timestamp.
+	rotateAnnotation = "sentry.sr.github.com/rotate"
+
+	defaultRotationGracePeriod = 24 * time.Hour
+
+	// maxDeletedPreviousKeys is the number of deleted keys kept in the
+	// lineage recorded in status.
+	maxDeletedPreviousKeys = 5
+)
+
+// rotationDue reports whether the key of instance must be rotated at now.
+func rotationDue(instance *sentryv1alpha1.ClientKey, now time.Time) bool {
+	if v := instance.Annotations[rotateAnnotation]; v != "" && v != instance.Status.RotationRequest {
+		return true
+	}
+	p := instance.Spec.RotationPolicy
+	if p == nil || p.Period == nil || p.Period.Duration <= 0 || instance.Status.KeyCreationTime == nil {
+		return false
+	}
+	return !now.Before(instance.Status.KeyCreationTime.Add(p.Period.Duration))
+}
+
+func rotationGracePeriod(instance *sentryv1alpha1.ClientKey) time.Duration {
+	if p := instance.Spec.RotationPolicy; p != nil && p.GracePeriod != nil {
+		return p.GracePeriod.Duration
+	}
+	return defaultRotationGracePeriod
+}
+
+// rotateClientKey creates a new key replacing the current key of instance,
+// which is recorded as a previous key expiring after the grace period.
+func (r *reconcilerSet) rotateClientKey(ctx context.Context, cli sentry.Client, instance *sentryv1alpha1.ClientKey, now time.Time) (*sentry.ClientKey, error) {
+	key, _, err := cli.CreateClientKey(ctx, instance.Status.OrganizationSlug, instance.Status.ProjectSlug, instance.Spec.Name)
+	if err != nil {
+		return nil, r.sentryError(instance, err, "failed to create client key for project %s", instance.Status.ProjectSlug)
+	}
+	r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonRotated, "Rotated Sentry client key %s to %s", instance.Status.ID, key.ID)
+
+	base := instance.DeepCopy()
+	instance.Status.PreviousKeys = append(instance.Status.PreviousKeys, sentryv1alpha1.ClientKeyPreviousKey{
+		ID:        instance.Status.ID,
+		RotatedAt: metav1.NewTime(now),
+		ExpiresAt: metav1.NewTime(now.Add(rotationGracePeriod(instance))),
+	})
+	instance.Status.ID = key.ID
+	instance.Status.KeyCreationTime = &metav1.Time{Time: now}
+	instance.Status.RotationRequest = instance.Annotations[rotateAnnotation]
+
+	// Record the new key before writing it to the Secret and ConfigMap, so
+	// that a failure does not leave it behind in Sentry and rotate again.
+	if err := r.saveStatus(ctx, instance, base); err != nil {
+		return nil, errors.Wrapf(err, "failed to record rotated client key %s", key.ID)
+	}
+	return key, nil
+}
+
+// expirePreviousKeys deactivates and deletes the previous keys of instance
+// whose grace period is over.
+func (r *reconcilerSet) expirePreviousKeys(ctx context.Context, cli sentry.Client, instance *sentryv1alpha1.ClientKey, now time.Time) error {
+	status := &instance.Status
+
+	for i := range status.PreviousKeys {
+		prev := &status.PreviousKeys[i]
+		if prev.Deleted || now.Before(prev.ExpiresAt.Time) {
+			continue
+		}
+
+		// Deactivate the key first, so that it stops accepting events even
+		// if it cannot be deleted.
+		inactive := false
+		_, _, err := cli.UpdateClientKey(ctx, status.OrganizationSlug, status.ProjectSlug, prev.ID, &sentry.ClientKeyUpdate{IsActive: &inactive})
+		if err == nil {
+			_, err = cli.DeleteClientKey(ctx, status.OrganizationSlug, status.ProjectSlug, prev.ID)
+		}
+		if err != nil && !sentry.IsNotFound(err) {
+			return r.sentryError(instance, err, "failed to delete previous client key %s", prev.ID)
+		}
+		if err == nil {
+			r.recorder.Eventf(instance, corev1.EventTypeNormal, eventReasonDeleted, "Deleted previous Sentry client key %s", prev.ID)
+		}
+		prev.Deleted = true
+	}
+
+	var deleted int
+	for _, prev := range status.PreviousKeys {
+		if prev.Deleted {
+			deleted++
+		}
+	}
+	var keys []sentryv1alpha1.ClientKeyPreviousKey
+	for _, prev := range status.PreviousKeys {
+		if prev.Deleted && deleted > maxDeletedPreviousKeys {
+			deleted--
+			continue
+		}
+		keys = append(keys, prev)
+	}
+	status.PreviousKeys = keys
+	return nil
+}
+
+// clientKeyRequeueAfter returns the duration after which instance must be
+// reconciled again to expire a previous key or rotate the current key, or 0
+// if there is no such deadline.
+func clientKeyRequeueAfter(instance *sentryv1alpha1.ClientKey, now time.Time) time.Duration {
+	var next time.Time
+	for _, prev := range instance.Status.PreviousKeys {
+		if !prev.Deleted && (next.IsZero() || prev.ExpiresAt.Time.Before(next)) {
+			next = prev.ExpiresAt.Time
+		}
+	}
+	if p := instance.Spec.RotationPolicy; p != nil && p.Period != nil && p.Period.Duration > 0 && instance.Status.KeyCreationTime != nil {
+		if t := instance.Status.KeyCreationTime.Add(p.Period.Duration); next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+	if next.IsZero() {
+		return 0
+	}
+	if d := next.Sub(now); d > 0 {
+		return d
+	}
+	return time.Second
+}
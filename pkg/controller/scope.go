@@ -0,0 +1,113 @@
+package sentrycontroller
+
+import (
+	"context"
+
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// NewCache returns a function creating the cache of a manager that only
+// watches the given namespaces, or all namespaces if there are none. Unlike
+// cache.MultiNamespacedCacheBuilder, the cache also holds the cluster-scoped
+// Organizations the controllers depend on.
+func NewCache(namespaces []string) cache.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		if len(namespaces) <= 1 {
+			if len(namespaces) == 1 {
+				opts.Namespace = namespaces[0]
+			}
+			return cache.New(config, opts)
+		}
+		cluster, err := cache.New(config, opts)
+		if err != nil {
+			return nil, err
+		}
+		namespaced, err := cache.MultiNamespacedCacheBuilder(namespaces)(config, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &scopedCache{Cache: namespaced, cluster: cluster}, nil
+	}
+}
+
+// scopedCache is a multi-namespace cache that delegates cluster-scoped
+// objects, which have no namespace to be looked up by, to a cluster cache.
+type scopedCache struct {
+	cache.Cache
+	cluster cache.Cache
+}
+
+func isClusterScoped(obj runtime.Object) bool {
+	switch obj.(type) {
+	case *sentryv1alpha1.Organization, *sentryv1alpha1.OrganizationList:
+		return true
+	}
+	return false
+}
+
+func (c *scopedCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if isClusterScoped(obj) {
+		return c.cluster.Get(ctx, key, obj)
+	}
+	return c.Cache.Get(ctx, key, obj)
+}
+
+func (c *scopedCache) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	if isClusterScoped(list) {
+		return c.cluster.List(ctx, list, opts...)
+	}
+	return c.Cache.List(ctx, list, opts...)
+}
+
+func (c *scopedCache) GetInformer(obj runtime.Object) (cache.Informer, error) {
+	if isClusterScoped(obj) {
+		return c.cluster.GetInformer(obj)
+	}
+	return c.Cache.GetInformer(obj)
+}
+
+func (c *scopedCache) GetInformerForKind(gvk schema.GroupVersionKind) (cache.Informer, error) {
+	if gvk == sentryv1alpha1.SchemeGroupVersion.WithKind("Organization") {
+		return c.cluster.GetInformerForKind(gvk)
+	}
+	return c.Cache.GetInformerForKind(gvk)
+}
+
+func (c *scopedCache) Start(stop <-chan struct{}) error {
+	errc := make(chan error, 2)
+	go func() { errc <- c.cluster.Start(stop) }()
+	go func() { errc <- c.Cache.Start(stop) }()
+	return <-errc
+}
+
+func (c *scopedCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	return c.cluster.WaitForCacheSync(stop) && c.Cache.WaitForCacheSync(stop)
+}
+
+// selects returns whether obj matches the label selector of the set.
+func (r *reconcilerSet) selects(obj metav1.Object) bool {
+	return r.selector == nil || r.selector.Matches(labels.Set(obj.GetLabels()))
+}
+
+// selectorPredicate filters out the events of objects that do not match
+// selector. All events pass if selector is nil.
+func selectorPredicate(selector labels.Selector) predicate.Predicate {
+	matches := func(m metav1.Object) bool {
+		return selector == nil || selector.Matches(labels.Set(m.GetLabels()))
+	}
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return matches(e.Meta) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return matches(e.MetaNew) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return matches(e.Meta) },
+		GenericFunc: func(e event.GenericEvent) bool { return matches(e.Meta) },
+	}
+}
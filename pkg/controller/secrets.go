@@ -0,0 +1,228 @@
+package sentrycontroller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"text/template"
+
+	"github.com/pkg/errors"
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	"github.com/sr/kube-sentry-controller/pkg/sentry"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// secretTemplateData is the data the templates of a ClientKeySecretTemplate
+// are executed with.
+type secretTemplateData struct {
+	DSN          sentry.ClientKeyDSN
+	ID           string
+	Project      string
+	Organization string
+}
+
+// renderSecret returns the Secret holding the DSNs of key, laid out as
+// described by the SecretTemplate of instance.
+func renderSecret(instance *sentryv1alpha1.ClientKey, key *sentry.ClientKey) (*corev1.Secret, error) {
+	tmpl := instance.Spec.SecretTemplate
+	if tmpl == nil {
+		tmpl = &sentryv1alpha1.ClientKeySecretTemplate{}
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   instance.Namespace,
+			Name:        tmpl.Name,
+			Labels:      tmpl.Labels,
+			Annotations: tmpl.Annotations,
+		},
+		Type: tmpl.Type,
+	}
+	if secret.Name == "" {
+		secret.Name = instance.Name
+	}
+	if secret.Type == "" {
+		secret.Type = corev1.SecretTypeOpaque
+	}
+
+	var dsn sentry.ClientKeyDSN
+	if key.DSN != nil {
+		dsn = *key.DSN
+	}
+	if len(tmpl.Data) == 0 {
+		secret.Data = map[string][]byte{
+			"dsn.secret": []byte(dsn.Secret),
+			"dsn.csp":    []byte(dsn.CSP),
+			"dsn.public": []byte(dsn.Public),
+		}
+		return secret, nil
+	}
+
+	data := secretTemplateData{
+		DSN:          dsn,
+		ID:           instance.Status.ID,
+		Project:      instance.Status.ProjectSlug,
+		Organization: instance.Status.OrganizationSlug,
+	}
+	secret.Data = make(map[string][]byte, len(tmpl.Data))
+	for k, text := range tmpl.Data {
+		t, err := template.New(k).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, &templateError{op: "parse", key: k, err: err}
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return nil, &templateError{op: "execute", key: k, err: err}
+		}
+		secret.Data[k] = buf.Bytes()
+	}
+	return secret, nil
+}
+
+// syncSecret creates or updates the Secret holding the DSNs of key. The Secret
+// previously created for instance is deleted if the template renamed it. An
+// existing Secret that is not controlled by instance is left untouched.
+func (r *reconcilerSet) syncSecret(ctx context.Context, instance *sentryv1alpha1.ClientKey, key *sentry.ClientKey) error {
+	secret, err := renderSecret(instance, key)
+	if err != nil {
+		return err
+	}
+	if err := controllerutil.SetControllerReference(instance, secret, r.scheme); err != nil {
+		return errors.Wrap(err, "failed to set controller reference on secret")
+	}
+
+	found := &corev1.Secret{}
+	err = r.kube.Get(ctx, client.ObjectKey{Namespace: secret.Namespace, Name: secret.Name}, found)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	if exists && !metav1.IsControlledBy(found, instance) {
+		return r.notControlled(instance, "secret", found.Name)
+	}
+
+	if old := instance.Status.SecretName; old != "" && old != secret.Name {
+		if err := r.deleteControlled(ctx, instance, &corev1.Secret{}, old); err != nil {
+			return err
+		}
+	}
+	instance.Status.SecretName = secret.Name
+
+	if !exists {
+		return errors.Wrapf(r.kube.Create(ctx, secret), "failed to create secret")
+	}
+
+	// The type of a Secret is immutable.
+	if found.Type != secret.Type {
+		if err := r.kube.Delete(ctx, found); err != nil {
+			return errors.Wrapf(err, "failed to delete secret %s", found.Name)
+		}
+		return errors.Wrapf(r.kube.Create(ctx, secret), "failed to create secret")
+	}
+
+	// Labels and annotations set by others, e.g. by tools like Reloader, are
+	// preserved.
+	base := found.DeepCopy()
+	changed := !reflect.DeepEqual(secret.Data, found.Data)
+	found.Data = secret.Data
+	if mergeStrings(&found.Labels, secret.Labels) {
+		changed = true
+	}
+	if mergeStrings(&found.Annotations, secret.Annotations) {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return errors.Wrapf(r.kube.Patch(ctx, found, client.MergeFrom(base)), "failed to update secret")
+}
+
+// mergeStrings sets the entries of src in dst, and reports whether dst changed.
+func mergeStrings(dst *map[string]string, src map[string]string) bool {
+	var changed bool
+	for k, v := range src {
+		if cur, ok := (*dst)[k]; ok && cur == v {
+			continue
+		}
+		if *dst == nil {
+			*dst = make(map[string]string)
+		}
+		(*dst)[k] = v
+		changed = true
+	}
+	return changed
+}
+
+// ownedObject is a Kubernetes object that can be controlled by a ClientKey.
+type ownedObject interface {
+	runtime.Object
+	metav1.Object
+}
+
+// deleteControlled looks up obj by name in the namespace of instance, and
+// deletes it if it is controlled by instance.
+func (r *reconcilerSet) deleteControlled(ctx context.Context, instance *sentryv1alpha1.ClientKey, obj ownedObject, name string) error {
+	err := r.kube.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: name}, obj)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(obj, instance) {
+		return nil
+	}
+	if err := r.kube.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete %s", name)
+	}
+	return nil
+}
+
+// templateError is returned when a template of the secretTemplate of a
+// ClientKey cannot be parsed or executed, which only a change of the spec
+// fixes.
+type templateError struct {
+	op  string
+	key string
+	err error
+}
+
+func (e *templateError) Error() string {
+	return fmt.Sprintf("failed to %s template of secret key %s: %s", e.op, e.key, e.err)
+}
+
+func isInvalidTemplate(err error) bool {
+	_, ok := errors.Cause(err).(*templateError)
+	return ok
+}
+
+// notControlledError is returned when the Secret or ConfigMap a ClientKey
+// writes to already exists but is not controlled by the ClientKey.
+type notControlledError struct {
+	kind string
+	name string
+}
+
+func (e *notControlledError) Error() string {
+	return fmt.Sprintf("%s %s already exists and is not managed by this client key", e.kind, e.name)
+}
+
+func isNotControlled(err error) bool {
+	_, ok := errors.Cause(err).(*notControlledError)
+	return ok
+}
+
+// notControlled records a Warning event on instance about the object of the
+// given kind and name that it does not control, and returns a
+// notControlledError.
+func (r *reconcilerSet) notControlled(instance *sentryv1alpha1.ClientKey, kind, name string) error {
+	err := &notControlledError{kind: kind, name: name}
+	r.recorder.Event(instance, corev1.EventTypeWarning, eventReasonResourceExists, err.Error())
+	return err
+}
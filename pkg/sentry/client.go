@@ -8,29 +8,37 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
+
+	"golang.org/x/time/rate"
 )
 
 type Client interface {
 	GetOrganization(ctx context.Context, slug string) (*Organization, *http.Response, error)
 
+	ListTeams(ctx context.Context, org string) ([]*Team, *http.Response, error)
 	GetTeam(ctx context.Context, org, slug string) (*Team, *http.Response, error)
 	CreateTeam(ctx context.Context, org, name, slug string) (*Team, *http.Response, error)
 	UpdateTeam(ctx context.Context, org, slug, newName, newSlug string) (*Team, *http.Response, error)
 	DeleteTeam(ctx context.Context, org, slug string) (*http.Response, error)
 
+	ListProjects(ctx context.Context, org string) ([]*Project, *http.Response, error)
 	GetProject(ctx context.Context, org, slug string) (*Project, *http.Response, error)
 	CreateProject(ctx context.Context, org, team, name, slug string) (*Project, *http.Response, error)
-	UpdateProject(ctx context.Context, org, slug, newName, newSlug string) (*Project, *http.Response, error)
+	UpdateProject(ctx context.Context, org, slug string, update *ProjectUpdate) (*Project, *http.Response, error)
 	DeleteProject(ctx context.Context, org, slug string) (*http.Response, error)
+	AddProjectTeam(ctx context.Context, org, proj, team string) (*Project, *http.Response, error)
+	RemoveProjectTeam(ctx context.Context, org, proj, team string) (*http.Response, error)
 
 	GetClientKeys(ctx context.Context, org, proj string) ([]*ClientKey, *http.Response, error)
 	CreateClientKey(ctx context.Context, org, proj, name string) (*ClientKey, *http.Response, error)
-	UpdateClientKey(ctx context.Context, org, proj, id, name string) (*http.Response, error)
+	UpdateClientKey(ctx context.Context, org, proj, id string, update *ClientKeyUpdate) (*ClientKey, *http.Response, error)
 	DeleteClientKey(ctx context.Context, org, proj, id string) (*http.Response, error)
 }
 
 type Organization struct {
 	Slug string `json:"slug"`
+	Name string `json:"name,omitempty"`
 }
 
 type Team struct {
@@ -39,43 +47,96 @@ type Team struct {
 }
 
 type Project struct {
-	Slug string `json:"slug,omitempty"`
-	Name string `json:"name,omitempty"`
+	Slug  string  `json:"slug,omitempty"`
+	Name  string  `json:"name,omitempty"`
+	Teams []*Team `json:"teams,omitempty"`
+
+	Platform           string   `json:"platform,omitempty"`
+	DefaultEnvironment string   `json:"defaultEnvironment,omitempty"`
+	ResolveAge         int      `json:"resolveAge,omitempty"`
+	SubjectPrefix      string   `json:"subjectPrefix,omitempty"`
+	DataScrubber       bool     `json:"dataScrubber,omitempty"`
+	SensitiveFields    []string `json:"sensitiveFields,omitempty"`
+	SafeFields         []string `json:"safeFields,omitempty"`
+	ScrubIPAddresses   bool     `json:"scrubIPAddresses,omitempty"`
+	AllowedDomains     []string `json:"allowedDomains,omitempty"`
+}
+
+// ProjectUpdate holds the fields of a project to update. Nil fields are left
+// unchanged.
+type ProjectUpdate struct {
+	Slug *string `json:"slug,omitempty"`
+	Name *string `json:"name,omitempty"`
+
+	Platform           *string   `json:"platform,omitempty"`
+	DefaultEnvironment *string   `json:"defaultEnvironment,omitempty"`
+	ResolveAge         *int      `json:"resolveAge,omitempty"`
+	SubjectPrefix      *string   `json:"subjectPrefix,omitempty"`
+	DataScrubber       *bool     `json:"dataScrubber,omitempty"`
+	SensitiveFields    *[]string `json:"sensitiveFields,omitempty"`
+	SafeFields         *[]string `json:"safeFields,omitempty"`
+	ScrubIPAddresses   *bool     `json:"scrubIPAddresses,omitempty"`
+	AllowedDomains     *[]string `json:"allowedDomains,omitempty"`
 }
 
 type ClientKey struct {
 	ID   string        `json:"id"`
 	Name string        `json:"name"`
 	DSN  *ClientKeyDSN `json:"dsn"`
+
+	IsActive                bool                    `json:"isActive,omitempty"`
+	RateLimit               *ClientKeyRateLimit     `json:"rateLimit,omitempty"`
+	BrowserSDKVersion       string                  `json:"browserSdkVersion,omitempty"`
+	DynamicSDKLoaderOptions *ClientKeyLoaderOptions `json:"dynamicSdkLoaderOptions,omitempty"`
 }
 
-type ClientKeyDSN struct {
-	Secret string `json:"secret"`
-	Public string `json:"public"`
-	CSP    string `json:"csp"`
+// ClientKeyRateLimit limits the number of events accepted with a key to Count
+// per Window seconds.
+type ClientKeyRateLimit struct {
+	Count  int `json:"count"`
+	Window int `json:"window"`
 }
 
-type ErrorResponse struct {
-	Response *http.Response
-	Body     []byte
+// ClientKeyLoaderOptions are the features bundled by the browser SDK loader.
+type ClientKeyLoaderOptions struct {
+	HasReplay      bool `json:"hasReplay"`
+	HasPerformance bool `json:"hasPerformance"`
+	HasDebug       bool `json:"hasDebug"`
 }
 
-func (e *ErrorResponse) Error() string {
-	return fmt.Sprintf("%v %v: %d %s",
-		e.Response.Request.Method,
-		e.Response.Request.URL,
-		e.Response.StatusCode,
-		string(e.Body),
-	)
+// ClientKeyUpdate holds the fields of a client key to update. Nil fields are
+// left unchanged.
+type ClientKeyUpdate struct {
+	Name                    *string                 `json:"name,omitempty"`
+	IsActive                *bool                   `json:"isActive,omitempty"`
+	RateLimit               *ClientKeyRateLimit     `json:"rateLimit,omitempty"`
+	BrowserSDKVersion       *string                 `json:"browserSdkVersion,omitempty"`
+	DynamicSDKLoaderOptions *ClientKeyLoaderOptions `json:"dynamicSdkLoaderOptions,omitempty"`
+}
+
+type ClientKeyDSN struct {
+	Secret string `json:"secret"`
+	Public string `json:"public"`
+	CSP    string `json:"csp"`
+	// CDN is the URL of the browser SDK loader script.
+	CDN string `json:"cdn,omitempty"`
 }
 
 type httpClient struct {
-	http    *http.Client
-	baseURL *url.URL
+	http     *http.Client
+	baseURL  *url.URL
+	retry    RetryPolicy
+	limiter  *rate.Limiter
+	throttle throttle
+	metrics  *Metrics
 }
 
-func New(http *http.Client, baseURL *url.URL) Client {
-	return &httpClient{http: http, baseURL: baseURL}
+func New(http *http.Client, baseURL *url.URL, opts ...Option) Client {
+	c := &httpClient{http: http, baseURL: baseURL}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // https://docs.sentry.io/api/organizations/get-organization-details/
@@ -85,13 +146,27 @@ func (c *httpClient) GetOrganization(ctx context.Context, slug string) (*Organiz
 		return nil, nil, err
 	}
 	org := &Organization{}
-	resp, err := c.do(ctx, req, org)
+	resp, err := c.do(ctx, "GetOrganization", req, org)
 	if err != nil {
 		return nil, resp, err
 	}
 	return org, resp, nil
 }
 
+// https://docs.sentry.io/api/teams/get-organization-teams/
+func (c *httpClient) ListTeams(ctx context.Context, org string) ([]*Team, *http.Response, error) {
+	teams := []*Team{}
+	resp, err := c.list(ctx, "ListTeams", fmt.Sprintf("organizations/%s/teams/", org), func(item json.RawMessage) error {
+		team := &Team{}
+		teams = append(teams, team)
+		return json.Unmarshal(item, team)
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+	return teams, resp, nil
+}
+
 // https://docs.sentry.io/api/teams/get-team-details/
 func (c *httpClient) GetTeam(ctx context.Context, org, slug string) (*Team, *http.Response, error) {
 	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("teams/%s/%s/", org, slug), nil)
@@ -99,7 +174,7 @@ func (c *httpClient) GetTeam(ctx context.Context, org, slug string) (*Team, *htt
 		return nil, nil, err
 	}
 	team := &Team{}
-	resp, err := c.do(ctx, req, team)
+	resp, err := c.do(ctx, "GetTeam", req, team)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -117,7 +192,7 @@ func (c *httpClient) CreateTeam(ctx context.Context, org, name, slug string) (*T
 		return nil, nil, err
 	}
 	team := &Team{}
-	resp, err := c.do(ctx, req, team)
+	resp, err := c.do(ctx, "CreateTeam", req, team)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -135,7 +210,7 @@ func (c *httpClient) UpdateTeam(ctx context.Context, org, slug, newName, newSlug
 		return nil, nil, err
 	}
 	team := &Team{}
-	resp, err := c.do(ctx, req, team)
+	resp, err := c.do(ctx, "UpdateTeam", req, team)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -148,7 +223,21 @@ func (c *httpClient) DeleteTeam(ctx context.Context, org, slug string) (*http.Re
 	if err != nil {
 		return nil, err
 	}
-	return c.do(ctx, req, nil)
+	return c.do(ctx, "DeleteTeam", req, nil)
+}
+
+// https://docs.sentry.io/api/organizations/get-organization-projects/
+func (c *httpClient) ListProjects(ctx context.Context, org string) ([]*Project, *http.Response, error) {
+	projs := []*Project{}
+	resp, err := c.list(ctx, "ListProjects", fmt.Sprintf("organizations/%s/projects/", org), func(item json.RawMessage) error {
+		proj := &Project{}
+		projs = append(projs, proj)
+		return json.Unmarshal(item, proj)
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+	return projs, resp, nil
 }
 
 // https://docs.sentry.io/api/projects/get-project-details/
@@ -158,7 +247,7 @@ func (c *httpClient) GetProject(ctx context.Context, org, slug string) (*Project
 		return nil, nil, err
 	}
 	proj := &Project{}
-	resp, err := c.do(ctx, req, proj)
+	resp, err := c.do(ctx, "GetProject", req, proj)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -176,7 +265,7 @@ func (c *httpClient) CreateProject(ctx context.Context, org, team, name, slug st
 		return nil, nil, err
 	}
 	proj := &Project{}
-	resp, err := c.do(ctx, req, proj)
+	resp, err := c.do(ctx, "CreateProject", req, proj)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -184,17 +273,17 @@ func (c *httpClient) CreateProject(ctx context.Context, org, team, name, slug st
 }
 
 // https://docs.sentry.io/api/projects/put-project-details/
-func (c *httpClient) UpdateProject(ctx context.Context, org, slug, newName, newSlug string) (*Project, *http.Response, error) {
+func (c *httpClient) UpdateProject(ctx context.Context, org, slug string, update *ProjectUpdate) (*Project, *http.Response, error) {
 	req, err := c.newRequest(
 		http.MethodPut,
 		fmt.Sprintf("projects/%s/%s/", org, slug),
-		Project{Name: newName, Slug: newSlug},
+		update,
 	)
 	if err != nil {
 		return nil, nil, err
 	}
 	proj := &Project{}
-	resp, err := c.do(ctx, req, proj)
+	resp, err := c.do(ctx, "UpdateProject", req, proj)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -207,17 +296,40 @@ func (c *httpClient) DeleteProject(ctx context.Context, org, slug string) (*http
 	if err != nil {
 		return nil, err
 	}
-	return c.do(ctx, req, nil)
+	return c.do(ctx, "DeleteProject", req, nil)
 }
 
-// https://docs.sentry.io/api/projects/get-project-keys/
-func (c *httpClient) GetClientKeys(ctx context.Context, org, proj string) ([]*ClientKey, *http.Response, error) {
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("projects/%s/%s/keys/", org, proj), nil)
+// https://docs.sentry.io/api/projects/add-a-team-to-a-project/
+func (c *httpClient) AddProjectTeam(ctx context.Context, org, proj, team string) (*Project, *http.Response, error) {
+	req, err := c.newRequest(http.MethodPost, fmt.Sprintf("projects/%s/%s/teams/%s/", org, proj, team), nil)
 	if err != nil {
 		return nil, nil, err
 	}
+	p := &Project{}
+	resp, err := c.do(ctx, "AddProjectTeam", req, p)
+	if err != nil {
+		return nil, resp, err
+	}
+	return p, resp, nil
+}
+
+// https://docs.sentry.io/api/projects/delete-a-team-from-a-project/
+func (c *httpClient) RemoveProjectTeam(ctx context.Context, org, proj, team string) (*http.Response, error) {
+	req, err := c.newRequest(http.MethodDelete, fmt.Sprintf("projects/%s/%s/teams/%s/", org, proj, team), nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, "RemoveProjectTeam", req, nil)
+}
+
+// https://docs.sentry.io/api/projects/get-project-keys/
+func (c *httpClient) GetClientKeys(ctx context.Context, org, proj string) ([]*ClientKey, *http.Response, error) {
 	keys := []*ClientKey{}
-	resp, err := c.do(ctx, req, &keys)
+	resp, err := c.list(ctx, "GetClientKeys", fmt.Sprintf("projects/%s/%s/keys/", org, proj), func(item json.RawMessage) error {
+		key := &ClientKey{}
+		keys = append(keys, key)
+		return json.Unmarshal(item, key)
+	})
 	if err != nil {
 		return nil, resp, err
 	}
@@ -235,7 +347,7 @@ func (c *httpClient) CreateClientKey(ctx context.Context, org, proj, name string
 		return nil, nil, err
 	}
 	key := &ClientKey{}
-	resp, err := c.do(ctx, req, key)
+	resp, err := c.do(ctx, "CreateClientKey", req, key)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -243,16 +355,21 @@ func (c *httpClient) CreateClientKey(ctx context.Context, org, proj, name string
 }
 
 // https://docs.sentry.io/api/projects/put-project-key-details/
-func (c *httpClient) UpdateClientKey(ctx context.Context, org, proj, id, name string) (*http.Response, error) {
+func (c *httpClient) UpdateClientKey(ctx context.Context, org, proj, id string, update *ClientKeyUpdate) (*ClientKey, *http.Response, error) {
 	req, err := c.newRequest(
 		http.MethodPut,
 		fmt.Sprintf("projects/%s/%s/keys/%s/", org, proj, id),
-		ClientKey{Name: name},
+		update,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	key := &ClientKey{}
+	resp, err := c.do(ctx, "UpdateClientKey", req, key)
+	if err != nil {
+		return nil, resp, err
 	}
-	return c.do(ctx, req, nil)
+	return key, resp, nil
 }
 
 // https://docs.sentry.io/api/projects/delete-project-key-details/
@@ -261,27 +378,69 @@ func (c *httpClient) DeleteClientKey(ctx context.Context, org, proj, id string)
 	if err != nil {
 		return nil, err
 	}
-	return c.do(ctx, req, nil)
+	return c.do(ctx, "DeleteClientKey", req, nil)
 }
 
-func (c *httpClient) do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+// do sends req, retrying it according to the retry policy of c, and decodes
+// the response body into v. endpoint names the Client method making the
+// request, for metrics.
+func (c *httpClient) do(ctx context.Context, endpoint string, req *http.Request, v interface{}) (*http.Response, error) {
 	req = req.WithContext(ctx)
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, endpoint, req, v)
+		if !c.retry.shouldRetry(ctx, attempt, req.Method, resp, err) {
+			return resp, err
+		}
+
+		timer := time.NewTimer(c.retry.delay(attempt, resp, time.Now()))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+func (c *httpClient) send(ctx context.Context, endpoint string, req *http.Request, v interface{}) (*http.Response, error) {
+	if err := c.throttle.wait(ctx); err != nil {
+		return nil, err
+	}
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
 	resp, err := c.http.Do(req)
+	if c.metrics != nil {
+		c.metrics.observe(endpoint, req.Method, start, resp, err)
+	}
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	c.throttle.observe(resp)
 
 	if !(resp.StatusCode == http.StatusOK ||
 		resp.StatusCode == http.StatusCreated ||
 		resp.StatusCode == http.StatusNoContent) {
 		s, _ := ioutil.ReadAll(resp.Body)
-		return resp, &ErrorResponse{Response: resp, Body: s}
+		return resp, newErrorResponse(resp, s)
 	}
 
 	if v != nil {
 		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
-			return nil, err
+			return resp, &DecodeError{Response: resp, Err: err}
 		}
 	}
 
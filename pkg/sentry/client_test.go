@@ -0,0 +1,619 @@
+package sentry
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// testServer serves the given responses in order and records the requests it
+// received.
+type testServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses []testResponse
+	requests  []*http.Request
+	bodies    []string
+}
+
+type testResponse struct {
+	status  int
+	headers map[string]string
+	body    string
+}
+
+func newTestServer(t *testing.T, responses ...testResponse) *testServer {
+	s := &testServer{responses: responses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		body, _ := ioutil.ReadAll(r.Body)
+		s.requests = append(s.requests, r)
+		s.bodies = append(s.bodies, string(body))
+
+		if len(s.responses) == 0 {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		resp := s.responses[0]
+		s.responses = s.responses[1:]
+		for k, v := range resp.headers {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(resp.status)
+		fmt.Fprint(w, resp.body)
+	}))
+	return s
+}
+
+func (s *testServer) client(t *testing.T, opts ...Option) Client {
+	u, err := url.Parse(s.URL + "/api/0/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(s.Server.Client(), u, opts...)
+}
+
+func TestClientRetries(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+	for _, tc := range []struct {
+		name      string
+		responses []testResponse
+		call      func(Client) error
+
+		wantErr      bool
+		wantRequests int
+	}{
+		{
+			name: "retries idempotent request on server error",
+			responses: []testResponse{
+				{status: http.StatusServiceUnavailable},
+				{status: http.StatusOK, body: `{"slug": "team"}`},
+			},
+			call: func(c Client) error {
+				_, _, err := c.GetTeam(context.Background(), "org", "team")
+				return err
+			},
+			wantRequests: 2,
+		},
+		{
+			name: "does not retry non-idempotent request on server error",
+			responses: []testResponse{
+				{status: http.StatusInternalServerError},
+			},
+			call: func(c Client) error {
+				_, _, err := c.CreateTeam(context.Background(), "org", "team", "team")
+				return err
+			},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name: "retries non-idempotent request when rate limited",
+			responses: []testResponse{
+				{status: http.StatusTooManyRequests, headers: map[string]string{"Retry-After": "0"}},
+				{status: http.StatusCreated, body: `{"slug": "team"}`},
+			},
+			call: func(c Client) error {
+				_, _, err := c.CreateTeam(context.Background(), "org", "team", "team")
+				return err
+			},
+			wantRequests: 2,
+		},
+		{
+			name: "does not retry client errors",
+			responses: []testResponse{
+				{status: http.StatusNotFound, body: `{"detail": "The requested resource does not exist"}`},
+			},
+			call: func(c Client) error {
+				_, _, err := c.GetTeam(context.Background(), "org", "team")
+				return err
+			},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name: "does not retry malformed successful response",
+			responses: []testResponse{
+				{status: http.StatusOK, body: `{"slug": `},
+			},
+			call: func(c Client) error {
+				_, _, err := c.GetTeam(context.Background(), "org", "team")
+				return err
+			},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name: "gives up after max retries",
+			responses: []testResponse{
+				{status: http.StatusBadGateway},
+				{status: http.StatusBadGateway},
+				{status: http.StatusBadGateway},
+			},
+			call: func(c Client) error {
+				_, err := c.DeleteTeam(context.Background(), "org", "team")
+				return err
+			},
+			wantErr:      true,
+			wantRequests: 3,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newTestServer(t, tc.responses...)
+			defer s.Close()
+
+			err := tc.call(s.client(t, WithRetryPolicy(policy)))
+
+			if tc.wantErr && err == nil {
+				t.Fatal("want error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("want err to be nil, got: %q", err)
+			}
+			if want, got := tc.wantRequests, len(s.requests); want != got {
+				t.Fatalf("want %d request(s), got: %d", want, got)
+			}
+		})
+	}
+}
+
+func TestClientDecodeError(t *testing.T) {
+	s := newTestServer(t, testResponse{status: http.StatusOK, body: `[{"slug": "team"}]`})
+	defer s.Close()
+
+	_, resp, err := s.client(t).GetTeam(context.Background(), "org", "team")
+	if _, ok := err.(*DecodeError); !ok {
+		t.Fatalf("want decode error, got: %v", err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("want the response returned with the error, got: %v", resp)
+	}
+}
+
+func TestClientRetryResendsBody(t *testing.T) {
+	s := newTestServer(t,
+		testResponse{status: http.StatusTooManyRequests, headers: map[string]string{"Retry-After": "0"}},
+		testResponse{status: http.StatusOK, body: `{"slug": "new-slug"}`},
+	)
+	defer s.Close()
+
+	c := s.client(t, WithRetryPolicy(RetryPolicy{MaxRetries: 1}))
+	if _, _, err := c.UpdateTeam(context.Background(), "org", "old-slug", "New Name", "new-slug"); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 2, len(s.bodies); want != got {
+		t.Fatalf("want %d request(s), got: %d", want, got)
+	}
+	if s.bodies[0] == "" || s.bodies[0] != s.bodies[1] {
+		t.Errorf("want retried request body %q, got: %q", s.bodies[0], s.bodies[1])
+	}
+}
+
+func TestClientProjectTeams(t *testing.T) {
+	s := newTestServer(t,
+		testResponse{status: http.StatusCreated, body: `{"slug": "proj", "teams": [{"slug": "a"}, {"slug": "b"}]}`},
+		testResponse{status: http.StatusOK, body: `{"slug": "proj", "teams": [{"slug": "a"}]}`},
+	)
+	defer s.Close()
+
+	c := s.client(t)
+	proj, _, err := c.AddProjectTeam(context.Background(), "org", "proj", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, len(proj.Teams); want != got {
+		t.Fatalf("want %d team(s), got: %d", want, got)
+	}
+	if _, err := c.RemoveProjectTeam(context.Background(), "org", "proj", "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []string{
+		"POST /api/0/projects/org/proj/teams/b/",
+		"DELETE /api/0/projects/org/proj/teams/b/",
+	} {
+		if got := s.requests[i].Method + " " + s.requests[i].URL.Path; want != got {
+			t.Errorf("want request #%d %q, got: %q", i, want, got)
+		}
+	}
+}
+
+func TestClientUpdateProject(t *testing.T) {
+	s := newTestServer(t, testResponse{status: http.StatusOK, body: `{"slug": "proj", "resolveAge": 0}`})
+	defer s.Close()
+
+	age := 0
+	fields := []string{}
+	c := s.client(t)
+	if _, _, err := c.UpdateProject(context.Background(), "org", "proj", &ProjectUpdate{ResolveAge: &age, SafeFields: &fields}); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := `{"resolveAge":0,"safeFields":[]}`, strings.TrimSpace(s.bodies[0]); want != got {
+		t.Errorf("want request body %s, got: %s", want, got)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	now := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	policy := RetryPolicy{MaxRetries: 5, MinBackoff: time.Second, MaxBackoff: time.Minute}
+
+	for _, tc := range []struct {
+		name    string
+		attempt int
+		resp    *http.Response
+
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name:    "exponential backoff",
+			attempt: 2,
+			wantMin: 2 * time.Second,
+			wantMax: 4 * time.Second,
+		},
+		{
+			name:    "backoff is capped",
+			attempt: 10,
+			wantMin: time.Minute,
+			wantMax: time.Minute,
+		},
+		{
+			name: "retry-after seconds",
+			resp: &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"7"}},
+			},
+			wantMin: 7 * time.Second,
+			wantMax: 7 * time.Second,
+		},
+		{
+			name: "retry-after date",
+			resp: &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{"Retry-After": []string{now.Add(12 * time.Second).Format(http.TimeFormat)}},
+			},
+			wantMin: 12 * time.Second,
+			wantMax: 12 * time.Second,
+		},
+		{
+			name: "sentry rate limit reset",
+			resp: &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header: http.Header{
+					"X-Sentry-Rate-Limit-Remaining": []string{"0"},
+					"X-Sentry-Rate-Limit-Reset":     []string{fmt.Sprintf("%d", now.Add(30*time.Second).Unix())},
+				},
+			},
+			wantMin: 30 * time.Second,
+			wantMax: 30 * time.Second,
+		},
+		{
+			name: "retry-after is capped",
+			resp: &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"3600"}},
+			},
+			wantMin: time.Minute,
+			wantMax: time.Minute,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := policy.delay(tc.attempt, tc.resp, now)
+			if got < tc.wantMin || got > tc.wantMax {
+				t.Errorf("want delay between %s and %s, got: %s", tc.wantMin, tc.wantMax, got)
+			}
+		})
+	}
+}
+
+func TestClientListFollowsPages(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	link := func(cursor string, results bool) string {
+		return fmt.Sprintf(
+			`<%s/api/0/projects/org/proj/keys/?&cursor=0:0:1>; rel="previous"; results="false"; cursor="0:0:1", <%s/api/0/projects/org/proj/keys/?&cursor=%s>; rel="next"; results="%t"; cursor="%s"`,
+			s.URL, s.URL, cursor, results, cursor,
+		)
+	}
+	s.responses = []testResponse{
+		{status: http.StatusOK, headers: map[string]string{"Link": link("100:1:0", true)}, body: `[{"id": "1"}, {"id": "2"}]`},
+		{status: http.StatusOK, headers: map[string]string{"Link": link("100:2:0", true)}, body: `[{"id": "3"}]`},
+		{status: http.StatusOK, headers: map[string]string{"Link": link("100:3:0", false)}, body: `[{"id": "4"}]`},
+	}
+
+	keys, _, err := s.client(t).GetClientKeys(context.Background(), "org", "proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, k := range keys {
+		got = append(got, k.ID)
+	}
+	if want := []string{"1", "2", "3", "4"}; fmt.Sprint(want) != fmt.Sprint(got) {
+		t.Errorf("want keys %v, got: %v", want, got)
+	}
+	if want, got := "cursor=100:2:0", s.requests[2].URL.RawQuery; !strings.Contains(got, want) {
+		t.Errorf("want last request query to contain %q, got: %q", want, got)
+	}
+}
+
+func TestNextPage(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		link string
+
+		want   string
+		wantOK bool
+	}{
+		{
+			name: "no link header",
+		},
+		{
+			name:   "next page with results",
+			link:   `<https://sentry.io/api/0/x/?&cursor=1:0:1>; rel="previous"; results="false"; cursor="1:0:1", <https://sentry.io/api/0/x/?&cursor=1:1:0>; rel="next"; results="true"; cursor="1:1:0"`,
+			want:   "https://sentry.io/api/0/x/?&cursor=1:1:0",
+			wantOK: true,
+		},
+		{
+			name: "next page without results",
+			link: `<https://sentry.io/api/0/x/?&cursor=1:0:1>; rel="previous"; results="true"; cursor="1:0:1", <https://sentry.io/api/0/x/?&cursor=1:1:0>; rel="next"; results="false"; cursor="1:1:0"`,
+		},
+		{
+			name:   "next page without results attribute",
+			link:   `<https://sentry.io/api/0/x/?page=2>; rel="next"`,
+			want:   "https://sentry.io/api/0/x/?page=2",
+			wantOK: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &http.Response{Header: http.Header{}}
+			if tc.link != "" {
+				resp.Header.Set("Link", tc.link)
+			}
+			got, ok := nextPage(resp)
+			if ok != tc.wantOK {
+				t.Fatalf("want ok %v, got: %v", tc.wantOK, ok)
+			}
+			if got != tc.want {
+				t.Errorf("want next page %q, got: %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestErrorResponse(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		status int
+		body   string
+
+		wantDetail string
+		wantFields map[string][]string
+		wantMsg    string
+		check      func(error) bool
+	}{
+		{
+			name:       "not found detail",
+			status:     http.StatusNotFound,
+			body:       `{"detail": "The requested resource does not exist"}`,
+			wantDetail: "The requested resource does not exist",
+			wantMsg:    "404 The requested resource does not exist",
+			check:      IsNotFound,
+		},
+		{
+			name:       "conflict field errors",
+			status:     http.StatusConflict,
+			body:       `{"slug": ["The slug \"backend\" is already in use."]}`,
+			wantFields: map[string][]string{"slug": {`The slug "backend" is already in use.`}},
+			wantMsg:    `409 slug: The slug "backend" is already in use.`,
+			check:      IsConflict,
+		},
+		{
+			name:    "forbidden",
+			status:  http.StatusForbidden,
+			body:    `{"detail": "You do not have permission to perform this action."}`,
+			wantMsg: "403 You do not have permission to perform this action.",
+			check:   IsUnauthorized,
+		},
+		{
+			name:    "rate limited",
+			status:  http.StatusTooManyRequests,
+			body:    `not json`,
+			wantMsg: "429 not json",
+			check: func(err error) bool {
+				return IsRateLimited(err) && IsTransient(err)
+			},
+		},
+		{
+			name:   "server error",
+			status: http.StatusBadGateway,
+			check: func(err error) bool {
+				return IsTransient(err) && !IsNotFound(err)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := newErrorResponse(&http.Response{StatusCode: tc.status}, []byte(tc.body))
+			err := errors.Wrap(e, "failed")
+
+			if tc.wantDetail != "" && e.Detail != tc.wantDetail {
+				t.Errorf("want detail %q, got: %q", tc.wantDetail, e.Detail)
+			}
+			if tc.wantFields != nil && !reflect.DeepEqual(tc.wantFields, e.Fields) {
+				t.Errorf("want fields %v, got: %v", tc.wantFields, e.Fields)
+			}
+			if tc.wantMsg != "" && e.Error() != tc.wantMsg {
+				t.Errorf("want message %q, got: %q", tc.wantMsg, e.Error())
+			}
+			if !tc.check(err) {
+				t.Errorf("want error %q to be classified as %s", err, tc.name)
+			}
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	link := func(cursor string, results bool) string {
+		return fmt.Sprintf(`<%s/api/0/organizations/my-org/teams/?&cursor=%s>; rel="next"; results="%t"; cursor="%s"`, srv.URL, cursor, results, cursor)
+	}
+	srv.responses = []testResponse{
+		{status: http.StatusOK, body: `{"slug": "my-team"}`},
+		{status: http.StatusServiceUnavailable, body: `{"detail": "unavailable"}`},
+		{status: http.StatusNotFound, body: `{"detail": "The requested resource does not exist"}`},
+		{status: http.StatusOK, headers: map[string]string{"Link": link("100:1:0", true)}, body: `[{"slug": "a"}]`},
+		{status: http.StatusOK, headers: map[string]string{"Link": link("100:2:0", false)}, body: `[{"slug": "b"}]`},
+	}
+
+	m := NewMetrics()
+	cli := srv.client(t, WithMetrics(m), WithRetryPolicy(RetryPolicy{MaxRetries: 1}))
+
+	if _, _, err := cli.GetTeam(context.Background(), "my-org", "my-team"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.DeleteTeam(context.Background(), "my-org", "my-team"); !IsNotFound(err) {
+		t.Fatalf("want not found error, got: %v", err)
+	}
+	if _, _, err := cli.ListTeams(context.Background(), "my-org"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		labels []string
+		want   float64
+	}{
+		{labels: []string{"GetTeam", http.MethodGet, "200"}, want: 1},
+		{labels: []string{"DeleteTeam", http.MethodDelete, "503"}, want: 1},
+		{labels: []string{"DeleteTeam", http.MethodDelete, "404"}, want: 1},
+		{labels: []string{"DeleteTeam", http.MethodDelete, "200"}, want: 0},
+		{labels: []string{"ListTeams", http.MethodGet, "200"}, want: 2},
+	} {
+		if got := testutil.ToFloat64(m.requests.WithLabelValues(tc.labels...)); got != tc.want {
+			t.Errorf("want sentry_api_requests_total%v %v, got: %v", tc.labels, tc.want, got)
+		}
+	}
+	ch := make(chan prometheus.Metric, 10)
+	m.duration.Collect(ch)
+	if got := len(ch); got != 3 {
+		t.Errorf("want 3 latency histograms, got: %d", got)
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	m := NewMetrics()
+	cli := Instrument(&Fake{Orgs: []*Organization{{Slug: "my-org"}}}, m)
+
+	if _, _, err := cli.GetOrganization(context.Background(), "my-org"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := cli.GetTeam(context.Background(), "my-org", "my-team"); !IsNotFound(err) {
+		t.Fatalf("want not found error, got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		labels []string
+		want   float64
+	}{
+		{labels: []string{"GetOrganization", http.MethodGet, "200"}, want: 1},
+		{labels: []string{"GetTeam", http.MethodGet, "404"}, want: 1},
+	} {
+		if got := testutil.ToFloat64(m.requests.WithLabelValues(tc.labels...)); got != tc.want {
+			t.Errorf("want sentry_api_requests_total%v %v, got: %v", tc.labels, tc.want, got)
+		}
+	}
+}
+
+func TestCodeLabel(t *testing.T) {
+	for _, tc := range []struct {
+		resp *http.Response
+		err  error
+		want string
+	}{
+		{resp: &http.Response{StatusCode: http.StatusCreated}, want: "201"},
+		{err: errors.Wrap(&ErrorResponse{Response: &http.Response{StatusCode: http.StatusConflict}}, "failed"), want: "409"},
+		{err: errors.New("connection refused"), want: "error"},
+		{want: "unknown"},
+	} {
+		if got := codeLabel(tc.resp, tc.err); got != tc.want {
+			t.Errorf("want code %q, got: %q", tc.want, got)
+		}
+	}
+}
+
+func TestIsValidSlug(t *testing.T) {
+	for _, tc := range []struct {
+		slug string
+		want bool
+	}{
+		{"my-team", true},
+		{"my_team2", true},
+		{"", false},
+		{"My-Team", false},
+		{"my team", false},
+		{"my.team", false},
+		{"1234", false},
+		{strings.Repeat("a", MaxSlugLength), true},
+		{strings.Repeat("a", MaxSlugLength+1), false},
+	} {
+		if got := IsValidSlug(tc.slug); got != tc.want {
+			t.Errorf("IsValidSlug(%q): want %v, got: %v", tc.slug, tc.want, got)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{"my-team", "my-team"},
+		{"My Team", "my-team"},
+		{"  My   Team -- Two ", "my-team-two"},
+		{"my.team", "myteam"},
+		{"Team_Ops!", "team_ops"},
+		{strings.Repeat("ab ", 30), strings.Repeat("ab-", 16) + "ab"},
+	} {
+		got := Slugify(tc.name)
+		if got != tc.want {
+			t.Errorf("Slugify(%q): want %q, got: %q", tc.name, tc.want, got)
+		}
+		if !IsValidSlug(got) {
+			t.Errorf("Slugify(%q) = %q is not a valid slug", tc.name, got)
+		}
+	}
+}
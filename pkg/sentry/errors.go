@@ -0,0 +1,146 @@
+package sentry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrorResponse is returned when the Sentry API responds with a non-2xx status.
+type ErrorResponse struct {
+	Response *http.Response
+	Body     []byte
+
+	// Detail is the error message returned by the Sentry API, if any.
+	Detail string
+	// Fields holds the validation errors returned by the Sentry API, keyed by
+	// the name of the invalid field.
+	Fields map[string][]string
+}
+
+func newErrorResponse(resp *http.Response, body []byte) *ErrorResponse {
+	e := &ErrorResponse{Response: resp, Body: body}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return e
+	}
+	for k, v := range fields {
+		if k == "detail" {
+			_ = json.Unmarshal(v, &e.Detail)
+			continue
+		}
+		var msgs []string
+		if err := json.Unmarshal(v, &msgs); err != nil {
+			var msg string
+			if err := json.Unmarshal(v, &msg); err != nil {
+				continue
+			}
+			msgs = []string{msg}
+		}
+		if e.Fields == nil {
+			e.Fields = make(map[string][]string)
+		}
+		e.Fields[k] = msgs
+	}
+	return e
+}
+
+func (e *ErrorResponse) Error() string {
+	msg := e.message()
+	if e.Response.Request == nil {
+		return fmt.Sprintf("%d %s", e.Response.StatusCode, msg)
+	}
+	return fmt.Sprintf("%v %v: %d %s",
+		e.Response.Request.Method,
+		e.Response.Request.URL,
+		e.Response.StatusCode,
+		msg,
+	)
+}
+
+// DecodeError is returned when the body of a successful response from the
+// Sentry API cannot be decoded. Requests failing with a DecodeError are not
+// retried, as the response would most likely be the same.
+type DecodeError struct {
+	Response *http.Response
+	Err      error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Response.Request == nil {
+		return fmt.Sprintf("failed to decode %d response: %s", e.Response.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("%v %v: failed to decode %d response: %s",
+		e.Response.Request.Method,
+		e.Response.Request.URL,
+		e.Response.StatusCode,
+		e.Err,
+	)
+}
+
+func (e *ErrorResponse) message() string {
+	if e.Detail != "" {
+		return e.Detail
+	}
+	if len(e.Fields) > 0 {
+		var msgs []string
+		for k, v := range e.Fields {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", k, strings.Join(v, " ")))
+		}
+		sort.Strings(msgs)
+		return strings.Join(msgs, "; ")
+	}
+	return string(e.Body)
+}
+
+// IsNotFound returns true if err indicates that the requested Sentry object
+// does not exist.
+func IsNotFound(err error) bool {
+	return statusCode(err) == http.StatusNotFound
+}
+
+// IsConflict returns true if err indicates that the Sentry object could not
+// be created because one with the same slug already exists.
+func IsConflict(err error) bool {
+	return statusCode(err) == http.StatusConflict
+}
+
+// IsRateLimited returns true if err indicates that the request was rejected
+// because of rate limiting.
+func IsRateLimited(err error) bool {
+	return statusCode(err) == http.StatusTooManyRequests
+}
+
+// IsUnauthorized returns true if err indicates that the API token is invalid
+// or lacks the permissions required for the request.
+func IsUnauthorized(err error) bool {
+	code := statusCode(err)
+	return code == http.StatusUnauthorized || code == http.StatusForbidden
+}
+
+// IsTransient returns true if err is expected to go away when the request is
+// retried later, i.e. rate limiting, server and network errors.
+func IsTransient(err error) bool {
+	cause := errors.Cause(err)
+	if _, ok := cause.(net.Error); ok {
+		return true
+	}
+	code := statusCode(err)
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// statusCode returns the HTTP status code of the Sentry API response that
+// caused err, or 0 if err was not returned by the Sentry API.
+func statusCode(err error) int {
+	e, ok := errors.Cause(err).(*ErrorResponse)
+	if !ok || e.Response == nil {
+		return 0
+	}
+	return e.Response.StatusCode
+}
@@ -2,10 +2,8 @@ package sentry
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 )
 
 var _ Client = &Fake{}
@@ -24,29 +22,37 @@ func (s *Fake) GetOrganization(ctx context.Context, slug string) (*Organization,
 			return org, &http.Response{StatusCode: http.StatusOK}, nil
 		}
 	}
-	return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("organization not found")
+	return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
+}
+
+func (s *Fake) ListTeams(ctx context.Context, org string) ([]*Team, *http.Response, error) {
+	if !s.orgExists(org) {
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
+	}
+	return s.Teams, &http.Response{StatusCode: http.StatusOK}, nil
 }
 
 func (s *Fake) GetTeam(ctx context.Context, org, slug string) (*Team, *http.Response, error) {
 	if !s.orgExists(org) {
-		return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("organization not found")
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
 	}
 	for _, t := range s.Teams {
 		if t.Slug == slug {
 			return t, nil, nil
 		}
 	}
-	return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("team found found")
+	return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("team not found")
 }
 
 func (s *Fake) CreateTeam(ctx context.Context, org, name, slug string) (*Team, *http.Response, error) {
 	if !s.orgExists(org) {
-		return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("organization not found")
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
 	}
 	if slug == "" {
-		s := strings.ToLower(name)
-		s = strings.Replace(s, " ", "-", -1)
-		slug = s
+		slug = Slugify(name)
+	}
+	if s.teamExists(slug) {
+		return nil, &http.Response{StatusCode: http.StatusConflict}, conflict("A team with this slug already exists.")
 	}
 	t := &Team{Name: name, Slug: slug}
 	s.Teams = append(s.Teams, t)
@@ -55,7 +61,7 @@ func (s *Fake) CreateTeam(ctx context.Context, org, name, slug string) (*Team, *
 
 func (s *Fake) UpdateTeam(ctx context.Context, org, slug, newName, newSlug string) (*Team, *http.Response, error) {
 	if !s.orgExists(org) {
-		return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("organization not found")
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
 	}
 	for _, t := range s.Teams {
 		if t.Slug == slug {
@@ -68,15 +74,15 @@ func (s *Fake) UpdateTeam(ctx context.Context, org, slug, newName, newSlug strin
 			return t, &http.Response{StatusCode: http.StatusOK}, nil
 		}
 	}
-	return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("team not found")
+	return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("team not found")
 }
 
 func (s *Fake) DeleteTeam(ctx context.Context, org, slug string) (*http.Response, error) {
 	if !s.orgExists(org) {
-		return &http.Response{StatusCode: http.StatusNotFound}, errors.New("organization not found")
+		return &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
 	}
 	if !s.teamExists(slug) {
-		return &http.Response{StatusCode: http.StatusNotFound}, errors.New("team not found")
+		return &http.Response{StatusCode: http.StatusNotFound}, notFound("team not found")
 	}
 
 	teams := []*Team{}
@@ -89,58 +95,94 @@ func (s *Fake) DeleteTeam(ctx context.Context, org, slug string) (*http.Response
 	return &http.Response{StatusCode: http.StatusNoContent}, nil
 }
 
+func (s *Fake) ListProjects(ctx context.Context, org string) ([]*Project, *http.Response, error) {
+	if !s.orgExists(org) {
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
+	}
+	return s.Projects, &http.Response{StatusCode: http.StatusOK}, nil
+}
+
 func (s *Fake) GetProject(ctx context.Context, org, slug string) (*Project, *http.Response, error) {
 	if !s.orgExists(org) {
-		return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("organization not found")
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
 	}
 	for _, p := range s.Projects {
 		if p.Slug == slug {
 			return p, &http.Response{StatusCode: http.StatusOK}, nil
 		}
 	}
-	return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("project not found")
+	return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("project not found")
 }
 
 func (s *Fake) CreateProject(ctx context.Context, org, team, name, slug string) (*Project, *http.Response, error) {
 	if !s.orgExists(org) {
-		return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("organization not found")
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
 	}
 	if !s.teamExists(team) {
-		return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("team not found")
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("team not found")
 	}
 	if slug == "" {
-		slug = strings.ToLower(name)
-		slug = strings.Replace(slug, " ", "-", -1)
+		slug = Slugify(name)
+	}
+	if s.projectExists(slug) {
+		return nil, &http.Response{StatusCode: http.StatusConflict}, conflict("A project with this slug already exists.")
 	}
-	p := &Project{Name: name, Slug: slug}
+	p := &Project{Name: name, Slug: slug, Teams: []*Team{{Slug: team}}}
 	s.Projects = append(s.Projects, p)
 	return p, &http.Response{StatusCode: http.StatusCreated}, nil
 }
 
-func (s *Fake) UpdateProject(ctx context.Context, org, slug, newName, newSlug string) (*Project, *http.Response, error) {
+func (s *Fake) UpdateProject(ctx context.Context, org, slug string, update *ProjectUpdate) (*Project, *http.Response, error) {
 	if !s.orgExists(org) {
-		return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("organization not found")
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
 	}
 	for _, p := range s.Projects {
 		if p.Slug == slug {
-			if newName != "" {
-				p.Name = newName
+			if update.Name != nil {
+				p.Name = *update.Name
 			}
-			if newSlug != "" {
-				p.Slug = newSlug
+			if update.Slug != nil {
+				p.Slug = *update.Slug
+			}
+			if update.Platform != nil {
+				p.Platform = *update.Platform
+			}
+			if update.DefaultEnvironment != nil {
+				p.DefaultEnvironment = *update.DefaultEnvironment
+			}
+			if update.ResolveAge != nil {
+				p.ResolveAge = *update.ResolveAge
+			}
+			if update.SubjectPrefix != nil {
+				p.SubjectPrefix = *update.SubjectPrefix
+			}
+			if update.DataScrubber != nil {
+				p.DataScrubber = *update.DataScrubber
+			}
+			if update.SensitiveFields != nil {
+				p.SensitiveFields = *update.SensitiveFields
+			}
+			if update.SafeFields != nil {
+				p.SafeFields = *update.SafeFields
+			}
+			if update.ScrubIPAddresses != nil {
+				p.ScrubIPAddresses = *update.ScrubIPAddresses
+			}
+			if update.AllowedDomains != nil {
+				p.AllowedDomains = *update.AllowedDomains
 			}
 			return p, &http.Response{StatusCode: http.StatusOK}, nil
 		}
 	}
-	return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("project found found")
+	return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("project not found")
 }
 
 func (s *Fake) DeleteProject(ctx context.Context, org, slug string) (*http.Response, error) {
 	if !s.orgExists(org) {
-		return &http.Response{StatusCode: http.StatusNotFound}, errors.New("organization not found")
+		return &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
 	}
 	if !s.projectExists(slug) {
-		return &http.Response{StatusCode: http.StatusNotFound}, errors.New("project not found")
+		return &http.Response{StatusCode: http.StatusNotFound}, notFound("project not found")
 	}
 
 	var projs []*Project
@@ -153,26 +195,72 @@ func (s *Fake) DeleteProject(ctx context.Context, org, slug string) (*http.Respo
 	return &http.Response{StatusCode: http.StatusOK}, nil
 }
 
+func (s *Fake) AddProjectTeam(ctx context.Context, org, proj, team string) (*Project, *http.Response, error) {
+	if !s.orgExists(org) {
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
+	}
+	if !s.teamExists(team) {
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("team not found")
+	}
+	for _, p := range s.Projects {
+		if p.Slug != proj {
+			continue
+		}
+		for _, t := range p.Teams {
+			if t.Slug == team {
+				return p, &http.Response{StatusCode: http.StatusCreated}, nil
+			}
+		}
+		p.Teams = append(p.Teams, &Team{Slug: team})
+		return p, &http.Response{StatusCode: http.StatusCreated}, nil
+	}
+	return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("project not found")
+}
+
+func (s *Fake) RemoveProjectTeam(ctx context.Context, org, proj, team string) (*http.Response, error) {
+	if !s.orgExists(org) {
+		return &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
+	}
+	if !s.teamExists(team) {
+		return &http.Response{StatusCode: http.StatusNotFound}, notFound("team not found")
+	}
+	for _, p := range s.Projects {
+		if p.Slug != proj {
+			continue
+		}
+		var teams []*Team
+		for _, t := range p.Teams {
+			if t.Slug != team {
+				teams = append(teams, t)
+			}
+		}
+		p.Teams = teams
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}
+	return &http.Response{StatusCode: http.StatusNotFound}, notFound("project not found")
+}
+
 func (s *Fake) GetClientKeys(ctx context.Context, org, proj string) ([]*ClientKey, *http.Response, error) {
 	if !s.orgExists(org) {
-		return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("organization not found")
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
 	}
 	if !s.projectExists(proj) {
-		return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("project not found")
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("project not found")
 	}
 	return s.ClientKeys, nil, nil
 }
 
 func (s *Fake) CreateClientKey(ctx context.Context, org, proj, name string) (*ClientKey, *http.Response, error) {
 	if !s.orgExists(org) {
-		return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("organization not found")
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
 	}
 	if !s.projectExists(proj) {
-		return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("project not found")
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("project not found")
 	}
 	k := &ClientKey{
-		ID:   fmt.Sprintf("%d", (len(s.ClientKeys) + 1)),
-		Name: name,
+		ID:       fmt.Sprintf("%d", (len(s.ClientKeys) + 1)),
+		Name:     name,
+		IsActive: true,
 		DSN: &ClientKeyDSN{
 			Secret: "secret",
 			CSP:    "csp",
@@ -183,28 +271,42 @@ func (s *Fake) CreateClientKey(ctx context.Context, org, proj, name string) (*Cl
 	return k, &http.Response{StatusCode: http.StatusOK}, nil
 }
 
-func (s *Fake) UpdateClientKey(ctx context.Context, org, proj, id, name string) (*http.Response, error) {
+func (s *Fake) UpdateClientKey(ctx context.Context, org, proj, id string, update *ClientKeyUpdate) (*ClientKey, *http.Response, error) {
 	if !s.orgExists(org) {
-		return &http.Response{StatusCode: http.StatusNotFound}, errors.New("organization not found")
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
 	}
 	if !s.projectExists(proj) {
-		return &http.Response{StatusCode: http.StatusNotFound}, errors.New("project not found")
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("project not found")
 	}
 	for _, k := range s.ClientKeys {
 		if k.ID == id {
-			k.Name = name
-			return &http.Response{StatusCode: http.StatusOK}, nil
+			if update.Name != nil {
+				k.Name = *update.Name
+			}
+			if update.IsActive != nil {
+				k.IsActive = *update.IsActive
+			}
+			if update.RateLimit != nil {
+				k.RateLimit = update.RateLimit
+			}
+			if update.BrowserSDKVersion != nil {
+				k.BrowserSDKVersion = *update.BrowserSDKVersion
+			}
+			if update.DynamicSDKLoaderOptions != nil {
+				k.DynamicSDKLoaderOptions = update.DynamicSDKLoaderOptions
+			}
+			return k, &http.Response{StatusCode: http.StatusOK}, nil
 		}
 	}
-	return &http.Response{StatusCode: http.StatusNotFound}, errors.New("client key not found")
+	return nil, &http.Response{StatusCode: http.StatusNotFound}, notFound("client key not found")
 }
 
 func (s *Fake) DeleteClientKey(ctx context.Context, org, proj, id string) (*http.Response, error) {
 	if !s.orgExists(org) {
-		return &http.Response{StatusCode: http.StatusNotFound}, errors.New("organization not found")
+		return &http.Response{StatusCode: http.StatusNotFound}, notFound("organization not found")
 	}
 	if !s.projectExists(proj) {
-		return &http.Response{StatusCode: http.StatusNotFound}, errors.New("project not found")
+		return &http.Response{StatusCode: http.StatusNotFound}, notFound("project not found")
 	}
 	var found bool
 	for _, k := range s.ClientKeys {
@@ -214,7 +316,7 @@ func (s *Fake) DeleteClientKey(ctx context.Context, org, proj, id string) (*http
 		}
 	}
 	if !found {
-		return &http.Response{StatusCode: http.StatusNotFound}, errors.New("client key not found")
+		return &http.Response{StatusCode: http.StatusNotFound}, notFound("client key not found")
 	}
 
 	var keys []*ClientKey
@@ -253,3 +355,11 @@ func (s *Fake) teamExists(slug string) bool {
 	}
 	return false
 }
+
+func notFound(detail string) error {
+	return &ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}, Detail: detail}
+}
+
+func conflict(detail string) error {
+	return &ErrorResponse{Response: &http.Response{StatusCode: http.StatusConflict}, Detail: detail}
+}
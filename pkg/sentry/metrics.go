@@ -0,0 +1,216 @@
+package sentry
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics are Prometheus metrics about the Sentry API requests made by the
+// clients configured WithMetrics or returned by Instrument. Metrics is a
+// prometheus.Collector and must be registered to be exported.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewMetrics returns Metrics labelled by endpoint, i.e. the name of the Client
+// method, HTTP method and, for the request counter, HTTP status code. The code
+// is "error" for requests that failed without a response from the Sentry API.
+// Clients configured WithMetrics count every attempt of a retried request
+// with its own status code, clients returned by Instrument count calls.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "sentry_api_requests_total",
+				Help: "Number of Sentry API requests, by endpoint, method and status code.",
+			},
+			[]string{"endpoint", "method", "code"},
+		),
+		duration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "sentry_api_request_duration_seconds",
+				Help:    "Latency of Sentry API requests, by endpoint and method.",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"endpoint", "method"},
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.duration.Collect(ch)
+}
+
+func (m *Metrics) observe(endpoint, method string, start time.Time, resp *http.Response, err error) {
+	m.duration.WithLabelValues(endpoint, method).Observe(time.Since(start).Seconds())
+	m.requests.WithLabelValues(endpoint, method, codeLabel(resp, err)).Inc()
+}
+
+func codeLabel(resp *http.Response, err error) string {
+	if resp != nil {
+		return strconv.Itoa(resp.StatusCode)
+	}
+	if code := statusCode(err); code != 0 {
+		return strconv.Itoa(code)
+	}
+	if err != nil {
+		return "error"
+	}
+	return "unknown"
+}
+
+// WithMetrics records metrics about every HTTP request sent by the client,
+// including retries and the requests for further pages of list results, to
+// m. Sharing m between clients aggregates their metrics. Use Instrument for
+// Client implementations other than the one returned by New.
+func WithMetrics(m *Metrics) Option {
+	return func(c *httpClient) {
+		c.metrics = m
+	}
+}
+
+// Instrument returns a Client recording metrics about the calls made to c, of
+// any Client implementation. Each call is counted once, with the status code
+// of its final response, whether it was retried or paginated by c. Do not
+// instrument a client configured WithMetrics with the same Metrics, as its
+// requests would be counted twice.
+func Instrument(c Client, m *Metrics) Client {
+	return &instrumentedClient{client: c, metrics: m}
+}
+
+type instrumentedClient struct {
+	client  Client
+	metrics *Metrics
+}
+
+func (c *instrumentedClient) GetOrganization(ctx context.Context, slug string) (*Organization, *http.Response, error) {
+	start := time.Now()
+	org, resp, err := c.client.GetOrganization(ctx, slug)
+	c.metrics.observe("GetOrganization", http.MethodGet, start, resp, err)
+	return org, resp, err
+}
+
+func (c *instrumentedClient) ListTeams(ctx context.Context, org string) ([]*Team, *http.Response, error) {
+	start := time.Now()
+	teams, resp, err := c.client.ListTeams(ctx, org)
+	c.metrics.observe("ListTeams", http.MethodGet, start, resp, err)
+	return teams, resp, err
+}
+
+func (c *instrumentedClient) GetTeam(ctx context.Context, org, slug string) (*Team, *http.Response, error) {
+	start := time.Now()
+	team, resp, err := c.client.GetTeam(ctx, org, slug)
+	c.metrics.observe("GetTeam", http.MethodGet, start, resp, err)
+	return team, resp, err
+}
+
+func (c *instrumentedClient) CreateTeam(ctx context.Context, org, name, slug string) (*Team, *http.Response, error) {
+	start := time.Now()
+	team, resp, err := c.client.CreateTeam(ctx, org, name, slug)
+	c.metrics.observe("CreateTeam", http.MethodPost, start, resp, err)
+	return team, resp, err
+}
+
+func (c *instrumentedClient) UpdateTeam(ctx context.Context, org, slug, newName, newSlug string) (*Team, *http.Response, error) {
+	start := time.Now()
+	team, resp, err := c.client.UpdateTeam(ctx, org, slug, newName, newSlug)
+	c.metrics.observe("UpdateTeam", http.MethodPut, start, resp, err)
+	return team, resp, err
+}
+
+func (c *instrumentedClient) DeleteTeam(ctx context.Context, org, slug string) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.client.DeleteTeam(ctx, org, slug)
+	c.metrics.observe("DeleteTeam", http.MethodDelete, start, resp, err)
+	return resp, err
+}
+
+func (c *instrumentedClient) ListProjects(ctx context.Context, org string) ([]*Project, *http.Response, error) {
+	start := time.Now()
+	projects, resp, err := c.client.ListProjects(ctx, org)
+	c.metrics.observe("ListProjects", http.MethodGet, start, resp, err)
+	return projects, resp, err
+}
+
+func (c *instrumentedClient) GetProject(ctx context.Context, org, slug string) (*Project, *http.Response, error) {
+	start := time.Now()
+	proj, resp, err := c.client.GetProject(ctx, org, slug)
+	c.metrics.observe("GetProject", http.MethodGet, start, resp, err)
+	return proj, resp, err
+}
+
+func (c *instrumentedClient) CreateProject(ctx context.Context, org, team, name, slug string) (*Project, *http.Response, error) {
+	start := time.Now()
+	proj, resp, err := c.client.CreateProject(ctx, org, team, name, slug)
+	c.metrics.observe("CreateProject", http.MethodPost, start, resp, err)
+	return proj, resp, err
+}
+
+func (c *instrumentedClient) UpdateProject(ctx context.Context, org, slug string, update *ProjectUpdate) (*Project, *http.Response, error) {
+	start := time.Now()
+	proj, resp, err := c.client.UpdateProject(ctx, org, slug, update)
+	c.metrics.observe("UpdateProject", http.MethodPut, start, resp, err)
+	return proj, resp, err
+}
+
+func (c *instrumentedClient) DeleteProject(ctx context.Context, org, slug string) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.client.DeleteProject(ctx, org, slug)
+	c.metrics.observe("DeleteProject", http.MethodDelete, start, resp, err)
+	return resp, err
+}
+
+func (c *instrumentedClient) AddProjectTeam(ctx context.Context, org, proj, team string) (*Project, *http.Response, error) {
+	start := time.Now()
+	p, resp, err := c.client.AddProjectTeam(ctx, org, proj, team)
+	c.metrics.observe("AddProjectTeam", http.MethodPost, start, resp, err)
+	return p, resp, err
+}
+
+func (c *instrumentedClient) RemoveProjectTeam(ctx context.Context, org, proj, team string) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.client.RemoveProjectTeam(ctx, org, proj, team)
+	c.metrics.observe("RemoveProjectTeam", http.MethodDelete, start, resp, err)
+	return resp, err
+}
+
+func (c *instrumentedClient) GetClientKeys(ctx context.Context, org, proj string) ([]*ClientKey, *http.Response, error) {
+	start := time.Now()
+	keys, resp, err := c.client.GetClientKeys(ctx, org, proj)
+	c.metrics.observe("GetClientKeys", http.MethodGet, start, resp, err)
+	return keys, resp, err
+}
+
+func (c *instrumentedClient) CreateClientKey(ctx context.Context, org, proj, name string) (*ClientKey, *http.Response, error) {
+	start := time.Now()
+	key, resp, err := c.client.CreateClientKey(ctx, org, proj, name)
+	c.metrics.observe("CreateClientKey", http.MethodPost, start, resp, err)
+	return key, resp, err
+}
+
+func (c *instrumentedClient) UpdateClientKey(ctx context.Context, org, proj, id string, update *ClientKeyUpdate) (*ClientKey, *http.Response, error) {
+	start := time.Now()
+	key, resp, err := c.client.UpdateClientKey(ctx, org, proj, id, update)
+	c.metrics.observe("UpdateClientKey", http.MethodPut, start, resp, err)
+	return key, resp, err
+}
+
+func (c *instrumentedClient) DeleteClientKey(ctx context.Context, org, proj, id string) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.client.DeleteClientKey(ctx, org, proj, id)
+	c.metrics.observe("DeleteClientKey", http.MethodDelete, start, resp, err)
+	return resp, err
+}
@@ -0,0 +1,72 @@
+package sentry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// list requests urlStr and every following page advertised in the Link header
+// of the responses, calling fn for each item of each page. It returns the
+// response of the last page requested. endpoint names the Client method
+// listing the items, for metrics.
+//
+// https://docs.sentry.io/api/pagination/
+func (c *httpClient) list(ctx context.Context, endpoint, urlStr string, fn func(item json.RawMessage) error) (*http.Response, error) {
+	for {
+		req, err := c.newRequest(http.MethodGet, urlStr, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page []json.RawMessage
+		resp, err := c.do(ctx, endpoint, req, &page)
+		if err != nil {
+			return resp, err
+		}
+		for _, item := range page {
+			if err := fn(item); err != nil {
+				return resp, err
+			}
+		}
+
+		next, ok := nextPage(resp)
+		if !ok {
+			return resp, nil
+		}
+		urlStr = next
+	}
+}
+
+// nextPage returns the URL of the next page of results from the Link header of
+// resp. Sentry always advertises a next page, with a results attribute
+// indicating whether it contains any result.
+func nextPage(resp *http.Response) (string, bool) {
+	for _, link := range strings.Split(resp.Header.Get("Link"), ",") {
+		parts := strings.Split(link, ";")
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+
+		var next, results bool
+		results = true
+		for _, param := range parts[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			v := strings.Trim(kv[1], `"`)
+			switch kv[0] {
+			case "rel":
+				next = v == "next"
+			case "results":
+				results = v == "true"
+			}
+		}
+		if next && results {
+			return strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">"), true
+		}
+	}
+	return "", false
+}
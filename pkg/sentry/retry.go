@@ -0,0 +1,190 @@
+package sentry
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Option configures a Client returned by New.
+type Option func(*httpClient)
+
+// WithRetryPolicy configures how failed requests are retried.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *httpClient) {
+		c.retry = p
+	}
+}
+
+// WithRateLimiter makes every request, including retries, wait for a token
+// from l before being sent. Sharing a limiter between clients enforces a rate
+// limit across all of them.
+func WithRateLimiter(l *rate.Limiter) Option {
+	return func(c *httpClient) {
+		c.limiter = l
+	}
+}
+
+// RetryPolicy controls how requests that failed with a transient error are
+// retried. Requests rejected with 429 Too Many Requests are retried regardless
+// of their method. Transport errors and 5xx responses are only retried for
+// idempotent methods.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a request is retried. Zero
+	// disables retries.
+	MaxRetries int
+	// MinBackoff is the base delay before the first retry. It doubles on every
+	// subsequent retry and is randomized to avoid synchronized retries.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts, including the delay
+	// requested by the Sentry API through the Retry-After header.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is a RetryPolicy suitable for most callers.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: 500 * time.Millisecond,
+	MaxBackoff: 30 * time.Second,
+}
+
+// shouldRetry reports whether a request sent with method that resulted in
+// resp and err can be retried.
+func (p RetryPolicy) shouldRetry(ctx context.Context, attempt int, method string, resp *http.Response, err error) bool {
+	if err == nil || attempt >= p.MaxRetries || ctx.Err() != nil {
+		return false
+	}
+	if _, ok := err.(*DecodeError); ok {
+		// Errors decoding a successful response are not retried.
+		return false
+	}
+	e, ok := err.(*ErrorResponse)
+	if !ok {
+		// Transport errors leave resp nil.
+		return resp == nil && isIdempotent(method)
+	}
+	switch code := e.Response.StatusCode; {
+	case code == http.StatusTooManyRequests:
+		return true
+	case code >= 500:
+		return isIdempotent(method)
+	default:
+		return false
+	}
+}
+
+// delay returns how long to wait before retrying attempt. The Retry-After
+// header of resp takes precedence over the exponential backoff.
+func (p RetryPolicy) delay(attempt int, resp *http.Response, now time.Time) time.Duration {
+	var d time.Duration
+	if wait, ok := retryAfter(resp, now); ok {
+		d = wait
+	} else {
+		d = p.MinBackoff << uint(attempt)
+		if d > 0 {
+			d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+		}
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// retryAfter returns the delay requested by the Sentry API before the next
+// request, either through the standard Retry-After header or, for rate limited
+// requests, the X-Sentry-Rate-Limit-Reset header.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil {
+			return time.Duration(secs * float64(time.Second)), true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return nonNegative(t.Sub(now)), true
+		}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if t, ok := rateLimitReset(resp); ok {
+			return nonNegative(t.Sub(now)), true
+		}
+	}
+	return 0, false
+}
+
+// rateLimitReset returns the time at which the Sentry rate limit window
+// advertised by resp resets.
+func rateLimitReset(resp *http.Response) (time.Time, bool) {
+	v := resp.Header.Get("X-Sentry-Rate-Limit-Reset")
+	if v == "" {
+		return time.Time{}, false
+	}
+	secs, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(secs*float64(time.Second))), true
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// throttle holds back requests until the rate limit window reported by the
+// Sentry API resets once the client has exhausted its quota.
+type throttle struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// wait blocks until the current rate limit window is over or ctx is done.
+func (t *throttle) wait(ctx context.Context) error {
+	t.mu.Lock()
+	d := time.Until(t.until)
+	t.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// observe records the rate limit headers of resp.
+func (t *throttle) observe(resp *http.Response) {
+	if resp.Header.Get("X-Sentry-Rate-Limit-Remaining") != "0" {
+		return
+	}
+	reset, ok := rateLimitReset(resp)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	if reset.After(t.until) {
+		t.until = reset
+	}
+	t.mu.Unlock()
+}
@@ -0,0 +1,37 @@
+package sentry
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MaxSlugLength is the maximum length of organization, team and project
+// slugs.
+const MaxSlugLength = 50
+
+var (
+	slugRegexp        = regexp.MustCompile(`^[a-z0-9_-]+$`)
+	digitsRegexp      = regexp.MustCompile(`^[0-9]+$`)
+	slugInvalidRegexp = regexp.MustCompile(`[^a-z0-9_\s-]`)
+	slugSpaceRegexp   = regexp.MustCompile(`[-\s]+`)
+)
+
+// IsValidSlug returns whether s is a valid organization, team or project slug:
+// made of lowercase letters, digits, dashes and underscores but not only
+// digits, and at most MaxSlugLength characters long.
+func IsValidSlug(s string) bool {
+	return len(s) <= MaxSlugLength && slugRegexp.MatchString(s) && !digitsRegexp.MatchString(s)
+}
+
+// Slugify returns the slug Sentry derives from the given name when it is
+// created without one, e.g. "my-team" for "My Team": it is lowercased,
+// stripped of the characters not allowed in slugs, and runs of spaces and
+// dashes are replaced with a single dash.
+func Slugify(name string) string {
+	s := slugInvalidRegexp.ReplaceAllString(strings.ToLower(name), "")
+	s = strings.Trim(slugSpaceRegexp.ReplaceAllString(s, "-"), "-_")
+	if len(s) > MaxSlugLength {
+		s = strings.Trim(s[:MaxSlugLength], "-_")
+	}
+	return s
+}
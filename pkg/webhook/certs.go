@@ -0,0 +1,143 @@
+package sentrywebhook
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// certValidity is the validity of self-signed certificates. They are
+// generated again every time the controller starts.
+const certValidity = 365 * 24 * time.Hour
+
+// WriteSelfSignedCert generates a CA, and a serving certificate for the given
+// DNS names signed by it, and writes the certificate and its key to dir as
+// tls.crt and tls.key, where the webhook server expects them. It returns the
+// PEM-encoded CA certificate, to be set as the CA bundle of the webhook
+// configurations. It is meant for local clusters without a certificate
+// manager.
+func WriteSelfSignedCert(dir string, dnsNames []string) ([]byte, error) {
+	now := time.Now()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate CA key")
+	}
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kube-sentry-controller-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(certValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, caKey.Public(), caKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA certificate")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate serving key")
+	}
+	cert := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(certValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, cert, ca, key.Public(), caKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create serving certificate")
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode serving key")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	if err := ioutil.WriteFile(filepath.Join(dir, "tls.crt"), certPEM, 0644); err != nil {
+		return nil, err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := ioutil.WriteFile(filepath.Join(dir, "tls.key"), keyPEM, 0600); err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), nil
+}
+
+// InjectCABundle sets the CA bundle of all the webhooks of the webhook
+// configurations of the controller, and of the conversion webhook of the
+// CRDs, so that the API server trusts the certificate returned by
+// WriteSelfSignedCert.
+//
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;update
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;update
+func InjectCABundle(ctx context.Context, kube client.Client, caBundle []byte) error {
+	mutating := &admissionregistrationv1beta1.MutatingWebhookConfiguration{}
+	if err := kube.Get(ctx, client.ObjectKey{Name: MutatingWebhookConfiguration}, mutating); err != nil {
+		return errors.Wrapf(err, "failed to get mutating webhook configuration %s", MutatingWebhookConfiguration)
+	}
+	for i := range mutating.Webhooks {
+		mutating.Webhooks[i].ClientConfig.CABundle = caBundle
+	}
+	if err := kube.Update(ctx, mutating); err != nil {
+		return errors.Wrapf(err, "failed to update mutating webhook configuration %s", MutatingWebhookConfiguration)
+	}
+
+	validating := &admissionregistrationv1beta1.ValidatingWebhookConfiguration{}
+	if err := kube.Get(ctx, client.ObjectKey{Name: ValidatingWebhookConfiguration}, validating); err != nil {
+		return errors.Wrapf(err, "failed to get validating webhook configuration %s", ValidatingWebhookConfiguration)
+	}
+	for i := range validating.Webhooks {
+		validating.Webhooks[i].ClientConfig.CABundle = caBundle
+	}
+	if err := kube.Update(ctx, validating); err != nil {
+		return errors.Wrapf(err, "failed to update validating webhook configuration %s", ValidatingWebhookConfiguration)
+	}
+
+	// The CRDs are handled as unstructured objects, the apiextensions types
+	// are not registered with the scheme of the manager.
+	for _, name := range ConvertedCRDs {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinition"})
+		if err := kube.Get(ctx, client.ObjectKey{Name: name}, crd); err != nil {
+			return errors.Wrapf(err, "failed to get custom resource definition %s", name)
+		}
+		strategy, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "strategy")
+		if strategy != "Webhook" {
+			continue
+		}
+		err := unstructured.SetNestedField(crd.Object, base64.StdEncoding.EncodeToString(caBundle), "spec", "conversion", "webhookClientConfig", "caBundle")
+		if err != nil {
+			return errors.Wrapf(err, "failed to set CA bundle of custom resource definition %s", name)
+		}
+		if err := kube.Update(ctx, crd); err != nil {
+			return errors.Wrapf(err, "failed to update custom resource definition %s", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package sentrywebhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	"github.com/sr/kube-sentry-controller/pkg/sentry"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// OrganizationAnnotation on a namespace sets the default organization of the
+// Teams, Projects and ClientKeys in it.
+const OrganizationAnnotation = "sentry.sr.github.com/organization"
+
+// defaulter fills in the spec fields of Teams, Projects and ClientKeys that
+// can be derived from their name and namespace, and normalizes their slugs.
+//
+// +kubebuilder:webhook:path=/mutate-sentry-sr-github-com-v1alpha1,mutating=true,failurePolicy=fail,groups=sentry.sr.github.com,resources=teams;projects;clientkeys,verbs=create;update,versions=v1alpha1,name=default.sentry.sr.github.com
+type defaulter struct {
+	kube    client.Reader
+	decoder *admission.Decoder
+
+	// organization is the default organization of the namespaces without
+	// OrganizationAnnotation. Optional.
+	organization string
+}
+
+func (d *defaulter) InjectDecoder(dec *admission.Decoder) error {
+	d.decoder = dec
+	return nil
+}
+
+func (d *defaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var obj runtime.Object
+	switch req.Kind.Kind {
+	case "Team":
+		obj = &sentryv1alpha1.Team{}
+	case "Project":
+		obj = &sentryv1alpha1.Project{}
+	case "ClientKey":
+		obj = &sentryv1alpha1.ClientKey{}
+	default:
+		return admission.Allowed("")
+	}
+	if err := d.decoder.DecodeRaw(req.Object, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	var org string
+	if needsOrganization(obj) {
+		var err error
+		if org, err = d.defaultOrganization(ctx, req.Namespace); err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+	}
+	if !setDefaults(obj, org) {
+		return admission.Allowed("")
+	}
+
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, b)
+}
+
+// defaultOrganization returns the organization set on the given namespace
+// with OrganizationAnnotation, or else the default organization of d.
+func (d *defaulter) defaultOrganization(ctx context.Context, namespace string) (string, error) {
+	ns := &corev1.Namespace{}
+	if err := d.kube.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return "", err
+	}
+	if org := ns.Annotations[OrganizationAnnotation]; org != "" {
+		return org, nil
+	}
+	return d.organization, nil
+}
+
+// needsOrganization returns whether obj has no organization, and no Team or
+// Project reference to get it from.
+func needsOrganization(obj runtime.Object) bool {
+	switch o := obj.(type) {
+	case *sentryv1alpha1.Team:
+		return o.Spec.OrganizationSlug == ""
+	case *sentryv1alpha1.Project:
+		return o.Spec.OrganizationSlug == "" && o.Spec.TeamRef == nil && len(o.Spec.TeamRefs) == 0
+	case *sentryv1alpha1.ClientKey:
+		return o.Spec.OrganizationSlug == "" && o.Spec.ProjectRef == nil
+	}
+	return false
+}
+
+// setDefaults defaults the slug or name of obj to its name, its organization
+// to org if it needs one, and normalizes its slugs. It returns false for
+// objects being deleted, which are left untouched.
+func setDefaults(obj runtime.Object, org string) bool {
+	switch o := obj.(type) {
+	case *sentryv1alpha1.Team:
+		if o.DeletionTimestamp != nil {
+			return false
+		}
+		o.Spec.Slug = defaultSlug(o.Spec.Slug, o.Name)
+		o.Spec.OrganizationSlug = defaultSlug(o.Spec.OrganizationSlug, org)
+	case *sentryv1alpha1.Project:
+		if o.DeletionTimestamp != nil {
+			return false
+		}
+		o.Spec.Slug = defaultSlug(o.Spec.Slug, o.Name)
+		if needsOrganization(o) {
+			o.Spec.OrganizationSlug = org
+		}
+		o.Spec.OrganizationSlug = normalizeSlug(o.Spec.OrganizationSlug)
+		o.Spec.TeamSlug = normalizeSlug(o.Spec.TeamSlug)
+		for i, slug := range o.Spec.Teams {
+			o.Spec.Teams[i] = normalizeSlug(slug)
+		}
+	case *sentryv1alpha1.ClientKey:
+		if o.DeletionTimestamp != nil {
+			return false
+		}
+		if o.Spec.Name == "" {
+			o.Spec.Name = o.Name
+		}
+		if needsOrganization(o) {
+			o.Spec.OrganizationSlug = org
+		}
+		o.Spec.OrganizationSlug = normalizeSlug(o.Spec.OrganizationSlug)
+		o.Spec.ProjectSlug = normalizeSlug(o.Spec.ProjectSlug)
+	default:
+		return false
+	}
+	return true
+}
+
+// defaultSlug returns slug, or else name, normalized.
+func defaultSlug(slug, name string) string {
+	if slug == "" {
+		slug = name
+	}
+	return normalizeSlug(slug)
+}
+
+// normalizeSlug returns the slug Sentry would derive from slug if it is not a
+// valid slug already.
+func normalizeSlug(slug string) string {
+	if slug == "" || sentry.IsValidSlug(slug) {
+		return slug
+	}
+	return sentry.Slugify(slug)
+}
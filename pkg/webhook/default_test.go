@@ -0,0 +1,149 @@
+package sentrywebhook
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestDefaulter(t *testing.T) {
+	if err := sentryv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+	decoder, err := admission.NewDecoder(scheme.Scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namespaces := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "testing",
+			Annotations: map[string]string{OrganizationAnnotation: "my-sentry-org"},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		obj       runtime.Object
+		namespace string
+		want      interface{}
+	}{
+		{
+			name: "team slug and organization",
+			obj: &sentryv1alpha1.Team{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "sentry.sr.github.com/v1alpha1", Kind: "Team"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "my-team"},
+			},
+			want: sentryv1alpha1.TeamSpec{Slug: "my-team", OrganizationSlug: "my-sentry-org"},
+		},
+		{
+			name: "team organization from flag",
+			obj: &sentryv1alpha1.Team{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "sentry.sr.github.com/v1alpha1", Kind: "Team"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "my-team"},
+			},
+			namespace: "other",
+			want:      sentryv1alpha1.TeamSpec{Slug: "my-team", OrganizationSlug: "default-org"},
+		},
+		{
+			name: "team slug normalized",
+			obj: &sentryv1alpha1.Team{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "sentry.sr.github.com/v1alpha1", Kind: "Team"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "my-team"},
+				Spec:       sentryv1alpha1.TeamSpec{Slug: "My Team", OrganizationSlug: "other-org"},
+			},
+			want: sentryv1alpha1.TeamSpec{Slug: "my-team", OrganizationSlug: "other-org"},
+		},
+		{
+			name: "project with team ref",
+			obj: &sentryv1alpha1.Project{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "sentry.sr.github.com/v1alpha1", Kind: "Project"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "my.proj"},
+				Spec: sentryv1alpha1.ProjectSpec{
+					TeamRef: &corev1.LocalObjectReference{Name: "my-team"},
+					Teams:   []string{"Ops Team"},
+				},
+			},
+			want: sentryv1alpha1.ProjectSpec{
+				Slug:    "myproj",
+				TeamRef: &corev1.LocalObjectReference{Name: "my-team"},
+				Teams:   []string{"ops-team"},
+			},
+		},
+		{
+			name: "client key name",
+			obj: &sentryv1alpha1.ClientKey{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "sentry.sr.github.com/v1alpha1", Kind: "ClientKey"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "my-key"},
+				Spec:       sentryv1alpha1.ClientKeySpec{ProjectSlug: "my-proj"},
+			},
+			want: sentryv1alpha1.ClientKeySpec{Name: "my-key", ProjectSlug: "my-proj", OrganizationSlug: "my-sentry-org"},
+		},
+		{
+			name: "team being deleted",
+			obj: &sentryv1alpha1.Team{
+				TypeMeta: metav1.TypeMeta{APIVersion: "sentry.sr.github.com/v1alpha1", Kind: "Team"},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "testing",
+					Name:              "my-team",
+					DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				},
+				Spec: sentryv1alpha1.TeamSpec{Slug: "My Team"},
+			},
+			want: sentryv1alpha1.TeamSpec{Slug: "My Team"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			d := &defaulter{
+				kube:         fake.NewFakeClient(namespaces...),
+				decoder:      decoder,
+				organization: "default-org",
+			}
+			req := newRequest(t, tc.obj, nil)
+			if tc.namespace != "" {
+				req.Namespace = tc.namespace
+			}
+			resp := d.Handle(context.Background(), req)
+			if !resp.Allowed {
+				t.Fatalf("expected request to be allowed, got: %v", resp.Result)
+			}
+
+			// The patches are checked by applying the defaults directly.
+			obj := tc.obj.DeepCopyObject()
+			org := ""
+			if needsOrganization(obj) {
+				if org, err = d.defaultOrganization(context.Background(), req.Namespace); err != nil {
+					t.Fatal(err)
+				}
+			}
+			changed := setDefaults(obj, org)
+			if changed && len(resp.Patches) == 0 {
+				t.Error("expected the response to have patches")
+			}
+
+			var got interface{}
+			switch o := obj.(type) {
+			case *sentryv1alpha1.Team:
+				got = o.Spec
+			case *sentryv1alpha1.Project:
+				got = o.Spec
+			case *sentryv1alpha1.ClientKey:
+				got = o.Spec
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("want spec %+v, got: %+v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package sentrywebhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	"github.com/sr/kube-sentry-controller/pkg/sentry"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const slugMessage = "must contain only lowercase letters, digits, dashes and underscores, not only digits"
+
+// validator rejects Teams, Projects and ClientKeys whose spec cannot be
+// synced, before Sentry rejects them during reconcilation.
+//
+// +kubebuilder:webhook:path=/validate-sentry-sr-github-com-v1alpha1,mutating=false,failurePolicy=fail,groups=sentry.sr.github.com,resources=teams;projects;clientkeys,verbs=create;update,versions=v1alpha1,name=validate.sentry.sr.github.com
+type validator struct {
+	kube    client.Client
+	decoder *admission.Decoder
+}
+
+func (v *validator) InjectClient(c client.Client) error {
+	v.kube = c
+	return nil
+}
+
+func (v *validator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+func (v *validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var (
+		errs field.ErrorList
+		err  error
+	)
+	switch req.Kind.Kind {
+	case "Team":
+		errs, err = v.validateTeam(req)
+	case "Project":
+		errs, err = v.validateProject(ctx, req)
+	case "ClientKey":
+		errs, err = v.validateClientKey(ctx, req)
+	default:
+		return admission.Allowed("")
+	}
+	if _, ok := err.(*decodeError); ok {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(errs) > 0 {
+		status := apierrors.NewInvalid(schema.GroupKind{Group: req.Kind.Group, Kind: req.Kind.Kind}, req.Name, errs).ErrStatus
+		return admission.Response{AdmissionResponse: admissionv1beta1.AdmissionResponse{Allowed: false, Result: &status}}
+	}
+	return admission.Allowed("")
+}
+
+// decodeError is returned when the object of an admission request cannot be
+// decoded, as opposed to errors looking up the objects it references.
+type decodeError struct {
+	err error
+}
+
+func (e *decodeError) Error() string {
+	return e.err.Error()
+}
+
+// decode decodes the object of req into obj, and its previous version into
+// old on updates.
+func (v *validator) decode(req admission.Request, obj, old runtime.Object) error {
+	if err := v.decoder.DecodeRaw(req.Object, obj); err != nil {
+		return &decodeError{err}
+	}
+	if req.Operation != admissionv1beta1.Update {
+		return nil
+	}
+	if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+		return &decodeError{err}
+	}
+	return nil
+}
+
+// validates returns whether the spec of obj needs validation. Updates of
+// objects being deleted or that leave the spec untouched are not validated,
+// so that objects created before the webhook can still be deleted and have
+// their status updated.
+func validates(req admission.Request, obj metav1.Object, spec, oldSpec interface{}) bool {
+	if req.Operation != admissionv1beta1.Update {
+		return true
+	}
+	return obj.GetDeletionTimestamp() == nil && !reflect.DeepEqual(spec, oldSpec)
+}
+
+func (v *validator) validateTeam(req admission.Request) (field.ErrorList, error) {
+	obj, old := &sentryv1alpha1.Team{}, &sentryv1alpha1.Team{}
+	if err := v.decode(req, obj, old); err != nil {
+		return nil, err
+	}
+	if !validates(req, obj, obj.Spec, old.Spec) {
+		return nil, nil
+	}
+
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+	errs = append(errs, validateSlug(spec.Child("slug"), obj.Spec.Slug, true)...)
+	errs = append(errs, validateSlug(spec.Child("organization"), obj.Spec.OrganizationSlug, true)...)
+	if req.Operation == admissionv1beta1.Update {
+		errs = append(errs, validateImmutable(spec.Child("organization"), obj.Spec.OrganizationSlug, old.Spec.OrganizationSlug)...)
+	}
+	return errs, nil
+}
+
+func (v *validator) validateProject(ctx context.Context, req admission.Request) (field.ErrorList, error) {
+	obj, old := &sentryv1alpha1.Project{}, &sentryv1alpha1.Project{}
+	if err := v.decode(req, obj, old); err != nil {
+		return nil, err
+	}
+	if !validates(req, obj, obj.Spec, old.Spec) {
+		return nil, nil
+	}
+
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+	errs = append(errs, validateSlug(spec.Child("slug"), obj.Spec.Slug, true)...)
+	errs = append(errs, validateSlug(spec.Child("organization"), obj.Spec.OrganizationSlug, obj.Spec.TeamRef == nil && len(obj.Spec.TeamRefs) == 0)...)
+	errs = append(errs, validateSlug(spec.Child("team"), obj.Spec.TeamSlug, false)...)
+	for i, slug := range obj.Spec.Teams {
+		errs = append(errs, validateSlug(spec.Child("teams").Index(i), slug, true)...)
+	}
+	if obj.Spec.TeamSlug == "" && obj.Spec.TeamRef == nil && len(obj.Spec.TeamRefs) == 0 && len(obj.Spec.Teams) == 0 {
+		errs = append(errs, field.Required(spec.Child("team"), "a project must belong to a team, set one of team, teamRef, teamRefs or teams"))
+	}
+	if req.Operation == admissionv1beta1.Update {
+		errs = append(errs, validateImmutable(spec.Child("organization"), obj.Spec.OrganizationSlug, old.Spec.OrganizationSlug)...)
+	}
+
+	// A project that gets its organization from its owning Team cannot move
+	// to a Team of another organization once synced.
+	path, ref := spec.Child("teamRef"), obj.Spec.TeamRef
+	if ref == nil && len(obj.Spec.TeamRefs) > 0 {
+		path, ref = spec.Child("teamRefs").Index(0), &obj.Spec.TeamRefs[0]
+	}
+	if ref != nil && obj.Spec.OrganizationSlug == "" && old.Status.OrganizationSlug != "" {
+		org, err := v.teamOrganization(ctx, req.Namespace, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		if org != "" && org != old.Status.OrganizationSlug {
+			errs = append(errs, field.Invalid(path, ref.Name, fmt.Sprintf("team is in organization %s, not %s", org, old.Status.OrganizationSlug)))
+		}
+	}
+	return errs, nil
+}
+
+func (v *validator) validateClientKey(ctx context.Context, req admission.Request) (field.ErrorList, error) {
+	obj, old := &sentryv1alpha1.ClientKey{}, &sentryv1alpha1.ClientKey{}
+	if err := v.decode(req, obj, old); err != nil {
+		return nil, err
+	}
+	if !validates(req, obj, obj.Spec, old.Spec) {
+		return nil, nil
+	}
+
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+	if obj.Spec.Name == "" {
+		errs = append(errs, field.Required(spec.Child("name"), ""))
+	}
+	errs = append(errs, validateSlug(spec.Child("organization"), obj.Spec.OrganizationSlug, obj.Spec.ProjectRef == nil)...)
+	errs = append(errs, validateSlug(spec.Child("project"), obj.Spec.ProjectSlug, obj.Spec.ProjectRef == nil)...)
+	if obj.Spec.KeyID != "" && !obj.Spec.Adopt {
+		errs = append(errs, field.Invalid(spec.Child("keyId"), obj.Spec.KeyID, "requires adopt"))
+	}
+	if req.Operation == admissionv1beta1.Update {
+		errs = append(errs, validateImmutable(spec.Child("organization"), obj.Spec.OrganizationSlug, old.Spec.OrganizationSlug)...)
+	}
+
+	// A referenced Project that does not exist yet is validated when it is
+	// created instead. A key that gets its organization from the Project
+	// cannot move to a Project of another organization once synced.
+	if ref := obj.Spec.ProjectRef; ref != nil {
+		org, err := v.projectOrganization(ctx, req.Namespace, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case org == "":
+		case obj.Spec.OrganizationSlug != "" && org != obj.Spec.OrganizationSlug:
+			errs = append(errs, field.Invalid(spec.Child("organization"), obj.Spec.OrganizationSlug, fmt.Sprintf("must match the organization %s of project %s", org, ref.Name)))
+		case obj.Spec.OrganizationSlug == "" && old.Status.OrganizationSlug != "" && org != old.Status.OrganizationSlug:
+			errs = append(errs, field.Invalid(spec.Child("projectRef"), ref.Name, fmt.Sprintf("project is in organization %s, not %s", org, old.Status.OrganizationSlug)))
+		}
+	}
+	return errs, nil
+}
+
+// teamOrganization returns the organization of the Team with the given name,
+// or an empty string if it does not exist.
+func (v *validator) teamOrganization(ctx context.Context, namespace, name string) (string, error) {
+	team := &sentryv1alpha1.Team{}
+	if err := v.kube.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, team); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if team.Spec.OrganizationSlug != "" {
+		return team.Spec.OrganizationSlug, nil
+	}
+	return team.Status.OrganizationSlug, nil
+}
+
+// projectOrganization returns the organization of the Project with the given
+// name, or an empty string if it does not exist or has none yet.
+func (v *validator) projectOrganization(ctx context.Context, namespace, name string) (string, error) {
+	proj := &sentryv1alpha1.Project{}
+	if err := v.kube.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, proj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if proj.Spec.OrganizationSlug != "" {
+		return proj.Spec.OrganizationSlug, nil
+	}
+	return proj.Status.OrganizationSlug, nil
+}
+
+func validateSlug(path *field.Path, slug string, required bool) field.ErrorList {
+	switch {
+	case slug == "" && required:
+		return field.ErrorList{field.Required(path, "")}
+	case slug == "":
+		return nil
+	case len(slug) > sentry.MaxSlugLength:
+		return field.ErrorList{field.TooLong(path, slug, sentry.MaxSlugLength)}
+	case !sentry.IsValidSlug(slug):
+		return field.ErrorList{field.Invalid(path, slug, slugMessage)}
+	}
+	return nil
+}
+
+func validateImmutable(path *field.Path, value, old string) field.ErrorList {
+	if value != old {
+		return field.ErrorList{field.Invalid(path, value, "field is immutable")}
+	}
+	return nil
+}
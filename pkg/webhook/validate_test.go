@@ -0,0 +1,365 @@
+package sentrywebhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	sentryv1alpha1 "github.com/sr/kube-sentry-controller/pkg/apis/sentry/v1alpha1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestValidator(t *testing.T) {
+	if err := sentryv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+	decoder, err := admission.NewDecoder(scheme.Scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	team := &sentryv1alpha1.Team{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "sentry.sr.github.com/v1alpha1", Kind: "Team"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "test-team"},
+		Spec:       sentryv1alpha1.TeamSpec{Slug: "my-team", OrganizationSlug: "my-sentry-org"},
+	}
+	project := &sentryv1alpha1.Project{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "sentry.sr.github.com/v1alpha1", Kind: "Project"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "test-proj"},
+		Spec:       sentryv1alpha1.ProjectSpec{Slug: "my-proj", OrganizationSlug: "my-sentry-org", TeamSlug: "my-team"},
+	}
+	otherTeam := &sentryv1alpha1.Team{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "other-team"},
+		Spec:       sentryv1alpha1.TeamSpec{Slug: "other-team", OrganizationSlug: "other-org"},
+	}
+	otherProject := &sentryv1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "other-proj"},
+		Spec:       sentryv1alpha1.ProjectSpec{Slug: "other-proj", OrganizationSlug: "other-org", TeamSlug: "other-team"},
+	}
+	key := &sentryv1alpha1.ClientKey{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "sentry.sr.github.com/v1alpha1", Kind: "ClientKey"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "test-key"},
+		Spec: sentryv1alpha1.ClientKeySpec{
+			Name:             "My Key",
+			OrganizationSlug: "my-sentry-org",
+			ProjectRef:       &corev1.LocalObjectReference{Name: "test-proj"},
+		},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		obj     runtime.Object
+		old     runtime.Object
+		wantErr string
+	}{
+		{name: "valid team", obj: team},
+		{
+			name: "team slug with uppercase letters",
+			obj: func() runtime.Object {
+				t := team.DeepCopy()
+				t.Spec.Slug = "My-Team"
+				return t
+			}(),
+			wantErr: `spec.slug: Invalid value: "My-Team"`,
+		},
+		{
+			name: "team slug with spaces",
+			obj: func() runtime.Object {
+				t := team.DeepCopy()
+				t.Spec.Slug = "my team"
+				return t
+			}(),
+			wantErr: `spec.slug: Invalid value: "my team"`,
+		},
+		{
+			name: "team without organization",
+			obj: func() runtime.Object {
+				t := team.DeepCopy()
+				t.Spec.OrganizationSlug = ""
+				return t
+			}(),
+			wantErr: "spec.organization: Required value",
+		},
+		{
+			name: "team organization changed",
+			obj: func() runtime.Object {
+				t := team.DeepCopy()
+				t.Spec.OrganizationSlug = "other-org"
+				return t
+			}(),
+			old:     team,
+			wantErr: "spec.organization: Invalid value: \"other-org\": field is immutable",
+		},
+		{
+			name: "team renamed",
+			obj: func() runtime.Object {
+				t := team.DeepCopy()
+				t.Spec.Slug = "new-team"
+				return t
+			}(),
+			old: team,
+		},
+		{
+			name: "invalid team with unchanged spec",
+			obj: func() runtime.Object {
+				t := team.DeepCopy()
+				t.Spec.Slug = "My Team"
+				t.Finalizers = []string{"sentry.sr.github.com"}
+				return t
+			}(),
+			old: func() runtime.Object {
+				t := team.DeepCopy()
+				t.Spec.Slug = "My Team"
+				return t
+			}(),
+		},
+		{
+			name: "invalid team being deleted",
+			obj: func() runtime.Object {
+				t := team.DeepCopy()
+				t.Spec.OrganizationSlug = "Other Org"
+				t.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				return t
+			}(),
+			old: team,
+		},
+		{name: "valid project", obj: project},
+		{
+			name: "project without team",
+			obj: func() runtime.Object {
+				p := project.DeepCopy()
+				p.Spec.TeamSlug = ""
+				return p
+			}(),
+			wantErr: "spec.team: Required value",
+		},
+		{
+			name: "project with organization from team ref",
+			obj: func() runtime.Object {
+				p := project.DeepCopy()
+				p.Spec.OrganizationSlug = ""
+				p.Spec.TeamSlug = ""
+				p.Spec.TeamRef = &corev1.LocalObjectReference{Name: "test-team"}
+				return p
+			}(),
+		},
+		{
+			name: "project moved to a team of another organization",
+			obj: func() runtime.Object {
+				p := project.DeepCopy()
+				p.Spec.OrganizationSlug = ""
+				p.Spec.TeamSlug = ""
+				p.Spec.TeamRef = &corev1.LocalObjectReference{Name: "other-team"}
+				p.Status.OrganizationSlug = "my-sentry-org"
+				return p
+			}(),
+			old: func() runtime.Object {
+				p := project.DeepCopy()
+				p.Spec.OrganizationSlug = ""
+				p.Spec.TeamSlug = ""
+				p.Spec.TeamRef = &corev1.LocalObjectReference{Name: "test-team"}
+				p.Status.OrganizationSlug = "my-sentry-org"
+				return p
+			}(),
+			wantErr: `spec.teamRef: Invalid value: "other-team": team is in organization other-org, not my-sentry-org`,
+		},
+		{
+			name: "unsynced project moved to a team of another organization",
+			obj: func() runtime.Object {
+				p := project.DeepCopy()
+				p.Spec.OrganizationSlug = ""
+				p.Spec.TeamSlug = ""
+				p.Spec.TeamRefs = []corev1.LocalObjectReference{{Name: "other-team"}}
+				return p
+			}(),
+			old: func() runtime.Object {
+				p := project.DeepCopy()
+				p.Spec.OrganizationSlug = ""
+				p.Spec.TeamSlug = ""
+				p.Spec.TeamRefs = []corev1.LocalObjectReference{{Name: "test-team"}}
+				return p
+			}(),
+		},
+		{
+			name: "project with invalid additional team",
+			obj: func() runtime.Object {
+				p := project.DeepCopy()
+				p.Spec.Teams = []string{"1234"}
+				return p
+			}(),
+			wantErr: `spec.teams[0]: Invalid value: "1234"`,
+		},
+		{
+			name: "project slug too long",
+			obj: func() runtime.Object {
+				p := project.DeepCopy()
+				p.Spec.Slug = strings.Repeat("a", 51)
+				return p
+			}(),
+			wantErr: "spec.slug: Too long",
+		},
+		{name: "valid client key", obj: key},
+		{
+			name: "client key in another organization than its project",
+			obj: func() runtime.Object {
+				k := key.DeepCopy()
+				k.Spec.OrganizationSlug = "other-org"
+				return k
+			}(),
+			wantErr: "must match the organization my-sentry-org of project test-proj",
+		},
+		{
+			name: "client key referencing a missing project",
+			obj: func() runtime.Object {
+				k := key.DeepCopy()
+				k.Spec.OrganizationSlug = "other-org"
+				k.Spec.ProjectRef.Name = "missing"
+				return k
+			}(),
+		},
+		{
+			name: "client key moved to a project of another organization",
+			obj: func() runtime.Object {
+				k := key.DeepCopy()
+				k.Spec.OrganizationSlug = ""
+				k.Spec.ProjectRef.Name = "other-proj"
+				k.Status.OrganizationSlug = "my-sentry-org"
+				return k
+			}(),
+			old: func() runtime.Object {
+				k := key.DeepCopy()
+				k.Spec.OrganizationSlug = ""
+				k.Status.OrganizationSlug = "my-sentry-org"
+				return k
+			}(),
+			wantErr: `spec.projectRef: Invalid value: "other-proj": project is in organization other-org, not my-sentry-org`,
+		},
+		{
+			name: "client key moved to a project of its organization",
+			obj: func() runtime.Object {
+				k := key.DeepCopy()
+				k.Spec.OrganizationSlug = ""
+				k.Status.OrganizationSlug = "my-sentry-org"
+				return k
+			}(),
+			old: func() runtime.Object {
+				k := key.DeepCopy()
+				k.Spec.OrganizationSlug = ""
+				k.Spec.ProjectRef.Name = "missing"
+				k.Status.OrganizationSlug = "my-sentry-org"
+				return k
+			}(),
+		},
+		{
+			name: "client key without project",
+			obj: func() runtime.Object {
+				k := key.DeepCopy()
+				k.Spec.ProjectRef = nil
+				return k
+			}(),
+			wantErr: "spec.project: Required value",
+		},
+		{
+			name: "client key id without adopt",
+			obj: func() runtime.Object {
+				k := key.DeepCopy()
+				k.Spec.KeyID = "1"
+				return k
+			}(),
+			wantErr: "spec.keyId: Invalid value: \"1\": requires adopt",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			v := &validator{kube: fake.NewFakeClient(team.DeepCopy(), otherTeam.DeepCopy(), project.DeepCopy(), otherProject.DeepCopy()), decoder: decoder}
+			resp := v.Handle(context.Background(), newRequest(t, tc.obj, tc.old))
+
+			if tc.wantErr == "" {
+				if !resp.Allowed {
+					t.Fatalf("expected request to be allowed, got: %s", resp.Result.Message)
+				}
+				return
+			}
+			if resp.Allowed {
+				t.Fatalf("expected request to be denied with %q", tc.wantErr)
+			}
+			if !strings.Contains(resp.Result.Message, tc.wantErr) {
+				t.Errorf("expected denial message to contain %q, got: %s", tc.wantErr, resp.Result.Message)
+			}
+		})
+	}
+}
+
+func TestValidatorErrors(t *testing.T) {
+	if err := sentryv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+	decoder, err := admission.NewDecoder(scheme.Scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &sentryv1alpha1.ClientKey{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "sentry.sr.github.com/v1alpha1", Kind: "ClientKey"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "test-key"},
+		Spec: sentryv1alpha1.ClientKeySpec{
+			Name:             "My Key",
+			OrganizationSlug: "my-sentry-org",
+			ProjectRef:       &corev1.LocalObjectReference{Name: "test-proj"},
+		},
+	}
+	v := &validator{kube: &failingClient{fake.NewFakeClient()}, decoder: decoder}
+
+	req := newRequest(t, key, nil)
+	req.Object.Raw = []byte("{")
+	if resp := v.Handle(context.Background(), req); resp.Allowed || resp.Result.Code != http.StatusBadRequest {
+		t.Errorf("want malformed object rejected with code %d, got: %+v", http.StatusBadRequest, resp.Result)
+	}
+
+	resp := v.Handle(context.Background(), newRequest(t, key, nil))
+	if resp.Allowed || resp.Result.Code != http.StatusInternalServerError {
+		t.Errorf("want failed project lookup rejected with code %d, got: %+v", http.StatusInternalServerError, resp.Result)
+	}
+}
+
+// failingClient is a client.Client whose reads fail, like an unavailable API
+// server.
+type failingClient struct {
+	client.Client
+}
+
+func (c *failingClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	return errors.New("connection refused")
+}
+
+func newRequest(t *testing.T, obj, old runtime.Object) admission.Request {
+	req := admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+		Operation: admissionv1beta1.Create,
+		Kind:      metav1.GroupVersionKind{Group: "sentry.sr.github.com", Version: "v1alpha1", Kind: obj.GetObjectKind().GroupVersionKind().Kind},
+		Namespace: "testing",
+		Object:    runtime.RawExtension{Raw: mustMarshal(t, obj)},
+	}}
+	if old != nil {
+		req.Operation = admissionv1beta1.Update
+		req.OldObject = runtime.RawExtension{Raw: mustMarshal(t, old)}
+	}
+	return req
+}
+
+func mustMarshal(t *testing.T, obj runtime.Object) []byte {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -0,0 +1,45 @@
+// Package sentrywebhook implements the admission and conversion webhooks of
+// the Sentry API types, served by the webhook server of a controller manager.
+package sentrywebhook
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+// Names of the webhook configurations registering the webhooks with the API
+// server, see config/webhook.
+const (
+	MutatingWebhookConfiguration   = "mutating-webhook-configuration"
+	ValidatingWebhookConfiguration = "validating-webhook-configuration"
+)
+
+// ConvertedCRDs are the names of the CRDs served in several versions,
+// converted by the conversion webhook.
+var ConvertedCRDs = []string{
+	"teams.sentry.sr.github.com",
+	"projects.sentry.sr.github.com",
+	"clientkeys.sentry.sr.github.com",
+}
+
+// Options configures the webhooks.
+type Options struct {
+	// DefaultOrganization of the objects in namespaces without the
+	// sentry.sr.github.com/organization annotation. Optional.
+	DefaultOrganization string
+}
+
+// Add registers the webhooks with the webhook server of mgr.
+//
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get
+func Add(mgr manager.Manager, opts Options) error {
+	srv := mgr.GetWebhookServer()
+	srv.Register("/mutate-sentry-sr-github-com-v1alpha1", &webhook.Admission{Handler: &defaulter{
+		kube:         mgr.GetAPIReader(),
+		organization: opts.DefaultOrganization,
+	}})
+	srv.Register("/validate-sentry-sr-github-com-v1alpha1", &webhook.Admission{Handler: &validator{}})
+	srv.Register("/convert", &conversion.Webhook{})
+	return nil
+}